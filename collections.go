@@ -0,0 +1,549 @@
+package tutl
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// KeyCount() tests that 'got' has exactly 'want' top-level keys.  'got'
+// can be a map, a struct (only exported fields are counted), a pointer to
+// either, or a 'string' or '[]byte' holding a JSON object.  If the count
+// is wrong, then a diagnostic is displayed (which also causes the unit
+// test to fail) that is similar to "Got 3 keys (a, b, c) not 2 for {desc}."
+//
+// KeyCount() returns whether the test passed.
+//
+func KeyCount(want int, got interface{}, desc string, t TestingT) bool {
+	t.Helper()
+	return Default.KeyCount(want, got, desc, t)
+}
+
+// See tutl.KeyCount() for documentation.
+func (o Options) KeyCount(
+	want int, got interface{}, desc string, t TestingT,
+) (passed bool) {
+	t.Helper()
+	defer o.hooks(desc)(&passed)
+	desc = o.descOf(desc)
+	keys, err := topKeys(got)
+	if nil != err {
+		t.Errorf("Can't count keys for %s: %v", desc, err)
+		return false
+	}
+	if want == len(keys) {
+		return true
+	}
+	t.Errorf("Got %d keys (%s) not %d for %s.",
+		len(keys), o.ReplaceNewlines(strings.Join(keys, ", ")), want, desc)
+	return false
+}
+
+// topKeys() returns the sorted list of top-level keys of a map, struct,
+// or JSON object.
+//
+func topKeys(v interface{}) ([]string, error) {
+	switch j := v.(type) {
+	case string:
+		return jsonKeys([]byte(j))
+	case []byte:
+		return jsonKeys(j)
+	}
+	rv := reflect.ValueOf(v)
+	for reflect.Ptr == rv.Kind() && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	keys := []string{}
+	switch rv.Kind() {
+	case reflect.Map:
+		for _, k := range rv.MapKeys() {
+			keys = append(keys, fmt.Sprint(k.Interface()))
+		}
+	case reflect.Struct:
+		rt := rv.Type()
+		for i := 0; i < rt.NumField(); i++ {
+			if "" == rt.Field(i).PkgPath {
+				keys = append(keys, rt.Field(i).Name)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("need a map, struct, or JSON object not %T", v)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// jsonKeys() returns the sorted list of keys of a JSON object.
+func jsonKeys(j []byte) ([]string, error) {
+	m := map[string]json.RawMessage{}
+	if err := json.Unmarshal(j, &m); nil != err {
+		return nil, err
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// IsEmpty() tests that 'got' is empty.  'got' can be a slice, map, string,
+// array, or channel (or a pointer to one of those) and is empty if its
+// length is 0.  A 'nil' value is also considered empty.
+//
+// If 'got' is not empty, then a diagnostic is displayed which also causes
+// the unit test to fail.  The diagnostic is similar to "Got non-empty
+// {got} for {desc}." where S() is used for 'got' (but long values are
+// truncated).
+//
+// IsEmpty() returns whether the test passed.
+//
+func IsEmpty(got interface{}, desc string, t TestingT) bool {
+	t.Helper()
+	return Default.IsEmpty(got, desc, t)
+}
+
+// See tutl.IsEmpty() for documentation.
+func (o Options) IsEmpty(
+	got interface{}, desc string, t TestingT,
+) (passed bool) {
+	t.Helper()
+	defer o.hooks(desc)(&passed)
+	desc = o.descOf(desc)
+	n, err := length(got)
+	if nil != err {
+		t.Errorf("Can't check if empty for %s: %v", desc, err)
+		return false
+	}
+	if 0 == n {
+		return true
+	}
+	t.Error("Got non-empty " + o.ReplaceNewlines(truncate(o.S(got), 40)) +
+		" for " + desc + ".")
+	return false
+}
+
+// NotEmpty() tests that 'got' is not empty [see IsEmpty()].  If it is
+// empty, then a diagnostic is displayed which also causes the unit test to
+// fail.  The diagnostic is similar to "Got empty {type} for {desc}."
+//
+// NotEmpty() returns whether the test passed.
+//
+func NotEmpty(got interface{}, desc string, t TestingT) bool {
+	t.Helper()
+	return Default.NotEmpty(got, desc, t)
+}
+
+// See tutl.NotEmpty() for documentation.
+func (o Options) NotEmpty(
+	got interface{}, desc string, t TestingT,
+) (passed bool) {
+	t.Helper()
+	defer o.hooks(desc)(&passed)
+	desc = o.descOf(desc)
+	n, err := length(got)
+	if nil != err {
+		t.Errorf("Can't check if empty for %s: %v", desc, err)
+		return false
+	}
+	if 0 < n {
+		return true
+	}
+	tgot := typeName(got)
+	t.Error("Got empty " + tgot + " for " + desc + ".")
+	return false
+}
+
+// length() returns the length of a slice, map, string, array, or channel
+// (or of what a pointer points to).  A 'nil' value has a length of 0.
+//
+func length(v interface{}) (int, error) {
+	if nil == v {
+		return 0, nil
+	}
+	rv := reflect.ValueOf(v)
+	for reflect.Ptr == rv.Kind() {
+		if rv.IsNil() {
+			return 0, nil
+		}
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Map, reflect.String, reflect.Array,
+		reflect.Chan:
+		return rv.Len(), nil
+	}
+	return 0, fmt.Errorf("%T has no length", v)
+}
+
+// truncate() returns 's' shortened to at most 'max' characters (ending in
+// "...") if it was longer than that.
+//
+func truncate(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	return string([]rune(s)[:max-3]) + "..."
+}
+
+// SameSet() tests that 'want' and 'got' contain the same strings, ignoring
+// order and duplicates.  Each string that is only in 'want' is reported
+// via a diagnostic similar to "Missing {s} for {desc}." and each that is
+// only in 'got' via one like "Got unexpected {s} for {desc}." (which also
+// cause the unit test to fail).
+//
+// SameSet() returns whether the test passed.
+//
+func SameSet(want, got []string, desc string, t TestingT) bool {
+	t.Helper()
+	return Default.SameSet(want, got, desc, t)
+}
+
+// See tutl.SameSet() for documentation.
+func (o Options) SameSet(
+	want, got []string, desc string, t TestingT,
+) (passed bool) {
+	t.Helper()
+	defer o.hooks(desc)(&passed)
+	desc = o.descOf(desc)
+	return 0 == o.setDiff(want, got, desc, t)
+}
+
+// setDiff() reports each string in 'want' that is not in 'got' and each
+// string in 'got' that is not in 'want' (ignoring duplicates) and returns
+// how many were reported.
+//
+func (o Options) setDiff(want, got []string, desc string, t TestingT) int {
+	t.Helper()
+	lim := o.limitFailures(t)
+	defer lim.done()
+	inWant := make(map[string]bool, len(want))
+	for _, s := range want {
+		inWant[s] = true
+	}
+	inGot := make(map[string]bool, len(got))
+	for _, s := range got {
+		inGot[s] = true
+	}
+	failures := 0
+	for _, s := range want {
+		if !inGot[s] {
+			failures++
+			inGot[s] = true // Only report once.
+			lim.Error(
+				"Missing " + o.ReplaceNewlines(o.S(s)) + " for " + desc + ".")
+		}
+	}
+	for _, s := range got {
+		if !inWant[s] {
+			failures++
+			inWant[s] = true
+			lim.Error("Got unexpected " + o.ReplaceNewlines(o.S(s)) +
+				" for " + desc + ".")
+		}
+	}
+	return failures
+}
+
+// IsPermutation() tests that 'want' and 'got' (which must each be a slice
+// or an array) contain the same elements, possibly in a different order.
+// Elements are compared by converting each to a string via V().
+//
+// If they are not permutations of each other, then a diagnostic is
+// displayed which also causes the unit test to fail.  The diagnostic
+// reconciles the two lists, showing which element of 'got' matched which
+// element of 'want' and which elements were not matched, like:
+//
+//      Got no permutation for {desc}:
+//          got[0] b <=> want[1] b
+//          got[1] x <=> (none)
+//          (none)   <=> want[0] a
+//
+// IsPermutation() returns whether the test passed.
+//
+func IsPermutation(want, got interface{}, desc string, t TestingT) bool {
+	t.Helper()
+	return Default.IsPermutation(want, got, desc, t)
+}
+
+// See tutl.IsPermutation() for documentation.
+func (o Options) IsPermutation(
+	want, got interface{}, desc string, t TestingT,
+) (passed bool) {
+	t.Helper()
+	defer o.hooks(desc)(&passed)
+	desc = o.descOf(desc)
+	wants, err := elems(want)
+	if nil == err {
+		var gots []interface{}
+		if gots, err = elems(got); nil == err {
+			return o.permutation(wants, gots, desc, t)
+		}
+	}
+	t.Errorf("Can't compare elements for %s: %v", desc, err)
+	return false
+}
+
+func (o Options) permutation(
+	wants, gots []interface{}, desc string, t TestingT,
+) bool {
+	t.Helper()
+	wStrs := make([]string, len(wants))
+	unused := make(map[string][]int)
+	for i, w := range wants {
+		wStrs[i] = o.ReplaceNewlines(o.V(w))
+		unused[wStrs[i]] = append(unused[wStrs[i]], i)
+	}
+	left := make([]string, 0, len(gots)+len(wants))
+	right := make([]string, 0, cap(left))
+	ok := len(wants) == len(gots)
+	for i, g := range gots {
+		v := o.ReplaceNewlines(o.V(g))
+		left = append(left, fmt.Sprintf("got[%d] %s", i, v))
+		if idx := unused[v]; 0 < len(idx) {
+			right = append(right, fmt.Sprintf("want[%d] %s", idx[0], v))
+			unused[v] = idx[1:]
+		} else {
+			right = append(right, "(none)")
+			ok = false
+		}
+	}
+	if ok {
+		return true
+	}
+	for i, w := range wStrs {
+		if idx := unused[w]; 0 < len(idx) && i == idx[0] {
+			left = append(left, "(none)")
+			right = append(right, fmt.Sprintf("want[%d] %s", i, w))
+			unused[w] = idx[1:]
+		}
+	}
+	wid := 0
+	for _, l := range left {
+		if n := utf8.RuneCountInString(l); wid < n {
+			wid = n
+		}
+	}
+	lines := []string{"Got no permutation for " + desc + ":"}
+	for i, l := range left {
+		pad := strings.Repeat(" ", wid-utf8.RuneCountInString(l))
+		lines = append(lines, "    "+l+pad+" <=> "+right[i])
+	}
+	t.Error(strings.Join(lines, "\n"))
+	return false
+}
+
+// elems() returns the elements of a slice or array.
+func elems(v interface{}) ([]interface{}, error) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+	default:
+		return nil, fmt.Errorf("need a slice or array not %T", v)
+	}
+	list := make([]interface{}, rv.Len())
+	for i := range list {
+		list[i] = rv.Index(i).Interface()
+	}
+	return list, nil
+}
+
+// InSet() tests that V(got) is equal to one of the 'allowed' strings.
+// This is a concise way to check that a value is from a fixed vocabulary:
+//
+//      u.InSet(resp.Status, "status", t, "active", "pending", "closed")
+//
+// If it is not, then a diagnostic similar to `Got "done" not one of
+// ("active", "pending", "closed") for {desc}.` is displayed (which also
+// causes the unit test to fail).
+//
+// InSet() returns whether the test passed.
+//
+func InSet(got interface{}, desc string, t TestingT, allowed ...string) bool {
+	t.Helper()
+	return Default.InSet(got, desc, t, allowed...)
+}
+
+// See tutl.InSet() for documentation.
+func (o Options) InSet(
+	got interface{}, desc string, t TestingT, allowed ...string,
+) (passed bool) {
+	t.Helper()
+	defer o.hooks(desc)(&passed)
+	desc = o.descOf(desc)
+	vgot := o.V(got)
+	quoted := make([]string, len(allowed))
+	for i, a := range allowed {
+		if vgot == a {
+			return true
+		}
+		quoted[i] = DoubleQuote(a)
+	}
+	o.gotNot(o.S(got), "one of ("+strings.Join(quoted, ", ")+")", desc, t)
+	return false
+}
+
+// ElementTypes() tests that 'got' has one element for each of 'wantTypes'
+// and that the type of each element [as shown by fmt.Sprintf("%T", elem),
+// just like HasType() uses] is the corresponding string in 'wantTypes'.
+// This suits checking mixed-type results, such as the tokens from a
+// parser:
+//
+//      u.ElementTypes(Tokenize("x = 1.5"), "tokens", t,
+//          "lex.Ident", "lex.Op", "float64")
+//
+// Each element of the wrong type is reported via a diagnostic similar to
+// "Got int not float64 at index 2 for {desc}." and a wrong number of
+// elements is reported via a diagnostic similar to "Got 2 elements not 3
+// for {desc}." (the elements that exist are still checked).  These also
+// cause the unit test to fail.
+//
+// ElementTypes() returns the number of failures reported.
+//
+func ElementTypes(
+	got []interface{}, desc string, t TestingT, wantTypes ...string,
+) int {
+	t.Helper()
+	return Default.ElementTypes(got, desc, t, wantTypes...)
+}
+
+// See tutl.ElementTypes() for documentation.
+func (o Options) ElementTypes(
+	got []interface{}, desc string, t TestingT, wantTypes ...string,
+) (failures int) {
+	t.Helper()
+	defer o.hooksN(desc)(&failures)
+	desc = o.descOf(desc)
+	lim := o.limitFailures(t)
+	defer lim.done()
+	if len(got) != len(wantTypes) {
+		failures++
+		lim.Errorf("Got %d elements not %d for %s.",
+			len(got), len(wantTypes), desc)
+	}
+	for i, want := range wantTypes {
+		if len(got) <= i {
+			break
+		}
+		if tgot := typeName(got[i]); want != tgot {
+			failures++
+			lim.Errorf("Got %s not %s at index %d for %s.", tgot, want, i, desc)
+		}
+	}
+	return failures
+}
+
+// NoDuplicates() tests that no two elements of 'got' (which must be a
+// slice or an array) are converted to the same string by V().  Each value
+// that appears more than once is reported via a diagnostic similar to
+// "Got duplicate {value} at indices 0, 3, 5 for {desc}." (which also
+// causes the unit test to fail).
+//
+// NoDuplicates() returns the number of duplicate elements found (not
+// counting the first occurrence of each value), or 1 if 'got' is not a
+// slice or array.
+//
+func NoDuplicates(got interface{}, desc string, t TestingT) int {
+	t.Helper()
+	return Default.NoDuplicates(got, desc, t)
+}
+
+// See tutl.NoDuplicates() for documentation.
+func (o Options) NoDuplicates(
+	got interface{}, desc string, t TestingT,
+) (failures int) {
+	t.Helper()
+	defer o.hooksN(desc)(&failures)
+	desc = o.descOf(desc)
+	list, err := elems(got)
+	if nil != err {
+		t.Errorf("Can't check elements for %s: %v", desc, err)
+		return 1
+	}
+	lim := o.limitFailures(t)
+	defer lim.done()
+	indices := make(map[string][]int, len(list))
+	var order []string
+	for i, e := range list {
+		v := o.V(e)
+		if _, ok := indices[v]; !ok {
+			order = append(order, v)
+		}
+		indices[v] = append(indices[v], i)
+	}
+	for _, v := range order {
+		at := indices[v]
+		if len(at) < 2 {
+			continue
+		}
+		failures += len(at) - 1
+		idx := make([]string, len(at))
+		for i, n := range at {
+			idx[i] = fmt.Sprint(n)
+		}
+		lim.Errorf("Got duplicate %s at indices %s for %s.",
+			o.ReplaceNewlines(o.S(list[at[0]])), strings.Join(idx, ", "), desc)
+	}
+	return failures
+}
+
+// ContainsSlice() tests that the elements of 'want' appear consecutively
+// (in the same order and with nothing between them) somewhere in 'got'.
+// Both 'want' and 'got' must be slices or arrays and elements are compared
+// via V(), so their types can differ:
+//
+//      u.ContainsSlice([]string{"HELO", "MAIL"}, cmds, "handshake", t)
+//
+// If the run is not found, then a diagnostic similar to "Got {got} not
+// containing {want} for {desc}." is displayed (which also causes the unit
+// test to fail).  An empty 'want' is found in any 'got'.
+//
+// ContainsSlice() returns whether the test passed.
+//
+func ContainsSlice(want, got interface{}, desc string, t TestingT) bool {
+	t.Helper()
+	return Default.ContainsSlice(want, got, desc, t)
+}
+
+// See tutl.ContainsSlice() for documentation.
+func (o Options) ContainsSlice(
+	want, got interface{}, desc string, t TestingT,
+) (passed bool) {
+	t.Helper()
+	defer o.hooks(desc)(&passed)
+	desc = o.descOf(desc)
+	wants, err := elems(want)
+	if nil == err {
+		var gots []interface{}
+		if gots, err = elems(got); nil == err {
+			if o.hasRun(wants, gots) {
+				return true
+			}
+			o.gotNot(o.S(got), "containing "+o.S(want), desc, t)
+			return false
+		}
+	}
+	t.Errorf("Can't compare elements for %s: %v", desc, err)
+	return false
+}
+
+// hasRun() returns whether 'run' appears consecutively within 'list'.
+func (o Options) hasRun(run, list []interface{}) bool {
+	vals := make([]string, len(run))
+	for i, r := range run {
+		vals[i] = o.V(r)
+	}
+	for start := 0; start+len(run) <= len(list); start++ {
+		i := 0
+		for i < len(run) && vals[i] == o.V(list[start+i]) {
+			i++
+		}
+		if len(run) == i {
+			return true
+		}
+	}
+	return false
+}
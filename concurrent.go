@@ -0,0 +1,122 @@
+package tutl
+
+import (
+	"sync"
+)
+
+// Concurrent() starts 'goroutines' goroutines that each call 'run' and
+// waits for all of them to finish.  The goroutines all wait at a barrier
+// until every one of them has started and then are released together,
+// which maximizes the chance that the calls to 'run' overlap:
+//
+//      tutl.Concurrent(t, 8, func() { counter.Incr() })
+//      u.Is(8, counter.Value(), "count after concurrent Incr()")
+//
+// Concurrent() does not itself detect data races; run your tests with
+// 'go test -race' for that.  It just makes contention likely.
+//
+// If any call to 'run' panics, then a diagnostic similar to "Panic in
+// goroutine 3 of 8: {panic}" is reported for each one (which also causes
+// the unit test to fail).  'run' must not call t.FailNow() (nor things
+// like t.Fatal() that call it).
+//
+// If 'goroutines' is not positive, then that is reported as a failure
+// and 'run' is never called.
+//
+// Concurrent() returns whether none of the calls panicked.
+//
+func Concurrent(t TestingT, goroutines int, run func()) bool {
+	t.Helper()
+	if goroutines <= 0 {
+		t.Errorf("Concurrent() needs a positive goroutine count"+
+			" in test code, not %d.", goroutines)
+		return false
+	}
+	panics := make([]interface{}, goroutines)
+	var started, finished sync.WaitGroup
+	release := make(chan struct{})
+	started.Add(goroutines)
+	finished.Add(goroutines)
+	for i := 0; i < goroutines; i++ {
+		go func(i int) {
+			defer finished.Done()
+			started.Done()
+			<-release
+			panics[i] = GetPanic(run)
+		}(i)
+	}
+	started.Wait()
+	close(release)
+	finished.Wait()
+
+	passed := true
+	for i, p := range panics {
+		if nil != p {
+			passed = false
+			t.Errorf("Panic in goroutine %d of %d: %v", i+1, goroutines, p)
+		}
+	}
+	return passed
+}
+
+// safeGoMu serializes the reports made by SafeGo() goroutines.
+var safeGoMu sync.Mutex
+
+// SafeGo() calls 'run' in a new goroutine that recovers from any panic in
+// 'run'.  Without this, a panic in a goroutine started by a test crashes
+// the whole test run without saying which test was to blame.  SafeGo()
+// returns a function that waits for 'run' to finish and then returns
+// whether it finished without panicking:
+//
+//      wait := tutl.SafeGo(t, "worker", func() { w.Process(jobs) })
+//      close(jobs)
+//      wait()
+//
+// If 'run' panics, then a diagnostic similar to "Panic in goroutine for
+// {desc}: {panic}" is reported (which also causes the unit test to fail),
+// followed by the stack trace of the goroutine starting from where it
+// panicked.  Reports from different SafeGo() goroutines are never made at
+// the same time.  'run' must not call t.FailNow() (nor things like
+// t.Fatal() that call it).
+//
+// The BeforeAssert hook [see Options] is called by SafeGo() and the
+// AfterAssert hook is called by the first call to the returned function.
+// You should call the returned function before your test finishes, as
+// reporting a failure after a test has completed causes a panic.
+//
+func SafeGo(t TestingT, desc string, run func()) (wait func() bool) {
+	t.Helper()
+	return Default.SafeGo(t, desc, run)
+}
+
+// See tutl.SafeGo() for documentation.
+func (o Options) SafeGo(
+	t TestingT, desc string, run func(),
+) (wait func() bool) {
+	t.Helper()
+	after := o.hooks(desc)
+	desc = o.descOf(desc)
+	done := make(chan struct{})
+	passed := true
+	go func() {
+		defer close(done)
+		defer func() {
+			p := recover()
+			if nil == p {
+				return
+			}
+			stack := panicStack()
+			safeGoMu.Lock()
+			defer safeGoMu.Unlock()
+			passed = false
+			t.Errorf("Panic in goroutine for %s: %v\n%s", desc, p, stack)
+		}()
+		run()
+	}()
+	var once sync.Once
+	return func() bool {
+		<-done
+		once.Do(func() { after(&passed) })
+		return passed
+	}
+}
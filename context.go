@@ -0,0 +1,124 @@
+package tutl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// IsCanceled() waits up to 'timeout' for 'ctx' to be canceled (for
+// ctx.Done() to be closed).  If that doesn't happen in time, then a
+// diagnostic similar to "Got context not canceled after 1s for {desc}."
+// is reported (which also causes the unit test to fail).
+//
+// The timeout is measured from when IsCanceled() is called.  A 'timeout'
+// of 0 (or less) just checks whether 'ctx' has already been canceled.
+//
+// If any 'causes' are given, then the cause of the cancellation (as
+// returned by context.Cause()) must also match one of them according to
+// errors.Is().  If it does not, then a diagnostic similar to "Got context
+// canceled ({cause}) not ({causes}) for {desc}." is reported:
+//
+//      u.IsCanceled(ctx, time.Second, "shutdown", t, ErrShutdown)
+//
+// If Options.Verbose is set, then the cause of the cancellation is logged
+// when the test passes.
+//
+// IsCanceled() returns whether the test passed.
+//
+func IsCanceled(
+	ctx context.Context, timeout time.Duration, desc string, t TestingT,
+	causes ...error,
+) bool {
+	t.Helper()
+	return Default.IsCanceled(ctx, timeout, desc, t, causes...)
+}
+
+// See tutl.IsCanceled() for documentation.
+func (o Options) IsCanceled(
+	ctx context.Context, timeout time.Duration, desc string, t TestingT,
+	causes ...error,
+) (passed bool) {
+	t.Helper()
+	defer o.hooks(desc)(&passed)
+	desc = o.descOf(desc)
+	if !waitDone(ctx, timeout) {
+		t.Errorf("Got context not canceled after %v for %s.", timeout, desc)
+		return false
+	}
+	cause := context.Cause(ctx)
+	if 0 < len(causes) && !isAny(cause, causes) {
+		want := make([]string, len(causes))
+		for i, c := range causes {
+			want[i] = fmt.Sprint(c)
+		}
+		t.Errorf("Got context canceled (%v) not (%s) for %s.",
+			cause, strings.Join(want, " or "), desc)
+		return false
+	}
+	if o.Verbose {
+		t.Logf("Got context canceled (%v) for %s.", cause, desc)
+	}
+	return true
+}
+
+// NotCanceled() is the opposite of IsCanceled().  It tests that 'ctx'
+// stays live (is not canceled) for all of 'timeout'.  If 'ctx' gets
+// canceled, then a diagnostic similar to "Got context canceled ({cause})
+// for {desc}." is reported immediately (which also causes the unit test
+// to fail), where {cause} is what context.Cause() returns.
+//
+// NotCanceled() returns whether the test passed.
+//
+func NotCanceled(
+	ctx context.Context, timeout time.Duration, desc string, t TestingT,
+) bool {
+	t.Helper()
+	return Default.NotCanceled(ctx, timeout, desc, t)
+}
+
+// See tutl.NotCanceled() for documentation.
+func (o Options) NotCanceled(
+	ctx context.Context, timeout time.Duration, desc string, t TestingT,
+) (passed bool) {
+	t.Helper()
+	defer o.hooks(desc)(&passed)
+	desc = o.descOf(desc)
+	if waitDone(ctx, timeout) {
+		t.Errorf("Got context canceled (%v) for %s.", context.Cause(ctx), desc)
+		return false
+	}
+	return true
+}
+
+// isAny() returns whether errors.Is() matches 'err' to any of 'targets'.
+func isAny(err error, targets []error) bool {
+	for _, target := range targets {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// waitDone() returns whether 'ctx' gets canceled within 'timeout'.
+func waitDone(ctx context.Context, timeout time.Duration) bool {
+	if timeout <= 0 {
+		select {
+		case <-ctx.Done():
+			return true
+		default:
+			return false
+		}
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return true
+	case <-timer.C:
+		return false
+	}
+}
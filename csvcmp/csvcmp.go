@@ -0,0 +1,183 @@
+/*
+
+Package csvcmp lets you compare CSV documents in your tests, cell by cell,
+so that a failure points at the first cell that differs:
+
+	import (
+		"testing"
+
+		"github.com/TyeMcQueen/go-tutl/csvcmp"
+	)
+
+	func TestReport(t *testing.T) {
+		csvcmp.CsvEqual(wantCsv, Report(data), "report", t)
+	}
+
+*/
+package csvcmp
+
+import (
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/TyeMcQueen/go-tutl"
+)
+
+// Options controls how CsvEqual() compares CSV documents.  The 'Default'
+// global is used by the non-method CsvEqual().
+//
+type Options struct {
+	// Header indicates that the first row of each document holds column
+	// names.  The header rows must match.  Column names are then included
+	// in diagnostics and, if IgnoreRowOrder is set, the header row is
+	// kept first.
+	//
+	Header bool
+
+	// IgnoreRowOrder makes the rows be compared as a multiset so that the
+	// documents only need to contain the same rows, in any order.
+	//
+	IgnoreRowOrder bool
+}
+
+// The 'csvcmp.Default' global holds the Options used by CsvEqual().
+var Default = Options{}
+
+// CsvEqual() parses 'want' and 'got' as CSV and tests that they contain
+// the same cells.  If they do not, then a diagnostic is displayed which
+// also causes the unit test to fail.  The diagnostic is similar to "Got
+// {got} not {want} at row 3, column 2 for {desc}." (rows and columns are
+// numbered starting at 1) and only the first difference is reported.
+//
+// If Default.IgnoreRowOrder is set, then each row that is only in 'want'
+// or only in 'got' is reported instead.
+//
+// CsvEqual() returns whether the test passed.
+//
+func CsvEqual(want, got string, desc string, t tutl.TestingT) bool {
+	t.Helper()
+	return Default.CsvEqual(want, got, desc, t)
+}
+
+// See csvcmp.CsvEqual() for documentation.
+func (o Options) CsvEqual(
+	want, got string, desc string, t tutl.TestingT,
+) bool {
+	t.Helper()
+	wRows, err := parse(want)
+	if nil != err {
+		t.Errorf("Invalid CSV in want for %s: %v", desc, err)
+		return false
+	}
+	gRows, err := parse(got)
+	if nil != err {
+		t.Errorf("Invalid CSV in got for %s: %v", desc, err)
+		return false
+	}
+	var header []string
+	skip := 0
+	if o.Header && 0 < len(wRows) {
+		header = wRows[0]
+		skip = 1
+	}
+	if !o.IgnoreRowOrder {
+		return compareRows(wRows, gRows, header, true, desc, t)
+	}
+	if 1 == skip {
+		if len(gRows) < 1 {
+			t.Errorf("Got 0 rows not %d for %s.", len(wRows), desc)
+			return false
+		}
+		if !compareRows(wRows[:1], gRows[:1], nil, true, desc, t) {
+			return false
+		}
+	}
+	return sameRows(wRows[skip:], gRows[skip:], desc, t)
+}
+
+// compareRows() compares 'want' and 'got' cell by cell, reporting the
+// first difference.  If 'header' is not 'nil', then it holds the names of
+// the columns.  If 'count' is set, then a different number of rows is
+// also reported.
+//
+func compareRows(
+	want, got [][]string, header []string, count bool,
+	desc string, t tutl.TestingT,
+) bool {
+	t.Helper()
+	for r := 0; r < len(want) && r < len(got); r++ {
+		wRow, gRow := want[r], got[r]
+		for c := 0; c < len(wRow) || c < len(gRow); c++ {
+			wCell, gCell := cell(wRow, c), cell(gRow, c)
+			if wCell != gCell {
+				col := fmt.Sprint(c + 1)
+				if c < len(header) && 0 < r {
+					col += " (" + header[c] + ")"
+				}
+				t.Errorf("Got %s not %s at row %d, column %s for %s.",
+					gCell, wCell, r+1, col, desc)
+				return false
+			}
+		}
+	}
+	if count && len(want) != len(got) {
+		t.Errorf("Got %d rows not %d for %s.", len(got), len(want), desc)
+		return false
+	}
+	return true
+}
+
+// sameRows() compares the rows of 'want' and 'got' ignoring their order,
+// reporting each row that is only in one of them.
+//
+func sameRows(want, got [][]string, desc string, t tutl.TestingT) bool {
+	t.Helper()
+	counts := make(map[string]int)
+	for _, row := range want {
+		counts[join(row)]++
+	}
+	for _, row := range got {
+		counts[join(row)]--
+	}
+	keys := make([]string, 0, len(counts))
+	for k, n := range counts {
+		if 0 != n {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if n := counts[k]; 0 < n {
+			t.Errorf("Missing %d row(s) %s for %s.", n, k, desc)
+		} else {
+			t.Errorf("Got %d unexpected row(s) %s for %s.", -n, k, desc)
+		}
+	}
+	return 0 == len(keys)
+}
+
+// parse() returns the rows from the CSV document 'doc'.
+func parse(doc string) ([][]string, error) {
+	r := csv.NewReader(strings.NewReader(doc))
+	r.FieldsPerRecord = -1
+	return r.ReadAll()
+}
+
+// cell() returns column 'c' of 'row' as a quoted string or "(missing)".
+func cell(row []string, c int) string {
+	if len(row) <= c {
+		return "(missing)"
+	}
+	return tutl.S(row[c])
+}
+
+// join() returns a readable, unambiguous representation of 'row'.
+func join(row []string) string {
+	cells := make([]string, len(row))
+	for i, c := range row {
+		cells[i] = tutl.S(c)
+	}
+	return "[" + strings.Join(cells, ",") + "]"
+}
@@ -0,0 +1,104 @@
+package csvcmp_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	u "github.com/TyeMcQueen/go-tutl"
+	"github.com/TyeMcQueen/go-tutl/csvcmp"
+)
+
+type mock struct {
+	output []string
+}
+
+func (m *mock) Failed() bool { return false }
+func (m *mock) Helper()      {}
+
+func (m *mock) Error(args ...interface{}) { m.Log(args...) }
+
+func (m *mock) Errorf(format string, args ...interface{}) {
+	m.Logf(format, args...)
+}
+
+func (m *mock) Log(args ...interface{}) {
+	m.output = append(m.output, fmt.Sprint(args...))
+}
+
+func (m *mock) Logf(format string, args ...interface{}) {
+	m.output = append(m.output, fmt.Sprintf(format, args...))
+}
+
+func (m *mock) isOutput(desc string, t *testing.T, want ...string) {
+	t.Helper()
+	if u.Is(len(want), len(m.output), desc+" count", t) {
+		for i, o := range want {
+			u.Is(o, m.output[i], u.S(desc, " ", i), t)
+		}
+	} else {
+		t.Log("Surprise output:\n", strings.Join(m.output, "\n"))
+	}
+	m.output = nil
+}
+
+func TestCsvEqual(t *testing.T) {
+	m := new(mock)
+
+	u.Is(true, csvcmp.CsvEqual("a,b\n1,2\n", "a,b\n1,2", "same", m), "same", t)
+	m.isOutput("same out", t)
+	u.Is(false, csvcmp.CsvEqual("a,b\n1,2\n", "a,b\n1,3\n", "rep", m),
+		"cell", t)
+	m.isOutput("cell out", t, `Got "3" not "2" at row 2, column 2 for rep.`)
+	u.Is(false, csvcmp.CsvEqual("1,2\n", "1\n", "short", m), "missing", t)
+	m.isOutput("missing out", t,
+		`Got (missing) not "2" at row 1, column 2 for short.`)
+	u.Is(false, csvcmp.CsvEqual("1\n", "1,2\n", "long", m), "extra", t)
+	m.isOutput("extra out", t,
+		`Got "2" not (missing) at row 1, column 2 for long.`)
+	u.Is(false, csvcmp.CsvEqual("1\n2\n", "1\n", "rows", m), "rows", t)
+	m.isOutput("rows out", t, "Got 1 rows not 2 for rows.")
+	u.Is(false, csvcmp.CsvEqual("1\n", `"a`, "bad", m), "invalid", t)
+	u.Is(1, len(m.output), "invalid count", t)
+	u.Like(m.output[0], "invalid out", t, "^Invalid CSV in got for bad: ")
+	m.output = nil
+}
+
+func TestHeader(t *testing.T) {
+	m := new(mock)
+	o := csvcmp.Options{Header: true}
+
+	u.Is(false, o.CsvEqual("id,name\n1,x\n", "id,name\n1,y\n", "h", m),
+		"named", t)
+	m.isOutput("named out", t,
+		`Got "y" not "x" at row 2, column 2 (name) for h.`)
+	u.Is(false, o.CsvEqual("id,name\n", "ID,name\n", "hdr", m), "header", t)
+	m.isOutput("header out", t, `Got "ID" not "id" at row 1, column 1 for hdr.`)
+}
+
+func TestIgnoreRowOrder(t *testing.T) {
+	m := new(mock)
+	o := csvcmp.Options{IgnoreRowOrder: true}
+
+	u.Is(true, o.CsvEqual("1,x\n2,y\n2,y\n", "2,y\n1,x\n2,y\n", "set", m),
+		"reordered", t)
+	m.isOutput("reordered out", t)
+	u.Is(false, o.CsvEqual("1,x\n2,y\n2,y\n", "2,y\n1,x\n3,z\n", "set", m),
+		"multiset", t)
+	m.isOutput("multiset out", t,
+		`Missing 1 row(s) ["2","y"] for set.`,
+		`Got 1 unexpected row(s) ["3","z"] for set.`)
+
+	o.Header = true
+	want := "id,n\n1,x\n2,y\n"
+	u.Is(true, o.CsvEqual(want, "id,n\n2,y\n1,x\n", "hdr", m), "header", t)
+	m.isOutput("header out", t)
+	u.Is(false, o.CsvEqual(want, "id,name\n1,x\n2,y\n", "hdr", m),
+		"header differs", t)
+	m.isOutput("header differs out", t,
+		`Got "name" not "n" at row 1, column 2 for hdr.`)
+	u.Is(false, o.CsvEqual(want, "", "empty", m), "no rows", t)
+	m.isOutput("no rows out", t, "Got 0 rows not 3 for empty.")
+	u.Is(false, o.CsvEqual(want, "id,n\n1,x\n", "fewer", m), "fewer", t)
+	m.isOutput("fewer out", t, `Missing 1 row(s) ["2","y"] for fewer.`)
+}
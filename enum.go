@@ -0,0 +1,152 @@
+package tutl
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	enumMu    sync.RWMutex
+	enumNames = map[reflect.Type]map[int]string{}
+)
+
+// RegisterEnum() registers the names for the values of an integer-based
+// enumeration type so that diagnostics show those names.  'example' can be
+// any value of the enumeration type (its value is ignored):
+//
+//      tutl.RegisterEnum(StatusActive, map[int]string{
+//          0: "StatusNew", 1: "StatusPending", 2: "StatusActive"})
+//
+// After this, S() formats StatusActive as "StatusActive(2)" and so Is()
+// reports a failure like "Got StatusPending(1) not StatusActive(2) for
+// {desc}.".  A value of that type that is missing from 'names' is shown
+// like "Status(7)".  V() is not changed so values are still compared
+// numerically.
+//
+// Registering a type again replaces its names.  Passing a 'nil' 'names'
+// removes the registration.  RegisterEnum() panics if 'example' does not
+// have an integer kind.  It is safe to call concurrently.
+//
+func RegisterEnum(example interface{}, names map[int]string) {
+	typ := reflect.TypeOf(example)
+	if _, ok := enumInt(example); !ok {
+		panic(fmt.Sprintf("RegisterEnum() needs an integer type not %v", typ))
+	}
+	enumMu.Lock()
+	defer enumMu.Unlock()
+	if nil == names {
+		delete(enumNames, typ)
+		return
+	}
+	copied := make(map[int]string, len(names))
+	for k, v := range names {
+		copied[k] = v
+	}
+	enumNames[typ] = copied
+}
+
+// IsValidEnum() tests that 'got' is one of the 'valid' values of an
+// enumeration type (compared via V()):
+//
+//      u.IsValidEnum(order.Status, "order status", t,
+//          StatusNew, StatusPending, StatusActive)
+//
+// If no 'valid' values are given, then the values registered for the type
+// of 'got' via RegisterEnum() are used.
+//
+// If 'got' is not valid, then a diagnostic similar to "Got Status(7) which
+// is not a valid Status (StatusNew(0), StatusPending(1), StatusActive(2))
+// for {desc}." is displayed (which also causes the unit test to fail).
+//
+// IsValidEnum() returns whether the test passed.
+//
+func IsValidEnum(
+	got interface{}, desc string, t TestingT, valid ...interface{},
+) bool {
+	t.Helper()
+	return Default.IsValidEnum(got, desc, t, valid...)
+}
+
+// See tutl.IsValidEnum() for documentation.
+func (o Options) IsValidEnum(
+	got interface{}, desc string, t TestingT, valid ...interface{},
+) (passed bool) {
+	t.Helper()
+	defer o.hooks(desc)(&passed)
+	desc = o.descOf(desc)
+	if 0 == len(valid) {
+		valid = registeredEnum(got)
+	}
+	vgot := o.V(got)
+	shown := make([]string, len(valid))
+	for i, v := range valid {
+		if vgot == o.V(v) {
+			return true
+		}
+		shown[i] = o.S(v)
+	}
+	typ := "<nil>"
+	if rt := reflect.TypeOf(got); nil != rt {
+		if typ = rt.Name(); "" == typ {
+			typ = rt.String()
+		}
+	}
+	t.Errorf("Got %s which is not a valid %s (%s) for %s.",
+		o.ReplaceNewlines(o.S(got)), typ, strings.Join(shown, ", "), desc)
+	return false
+}
+
+// registeredEnum() returns the values registered via RegisterEnum() for
+// the type of 'v', in numeric order.
+//
+func registeredEnum(v interface{}) []interface{} {
+	typ := reflect.TypeOf(v)
+	enumMu.RLock()
+	names := enumNames[typ]
+	enumMu.RUnlock()
+	nums := make([]int, 0, len(names))
+	for n := range names {
+		nums = append(nums, n)
+	}
+	sort.Ints(nums)
+	vals := make([]interface{}, len(nums))
+	for i, n := range nums {
+		vals[i] = reflect.ValueOf(n).Convert(typ).Interface()
+	}
+	return vals
+}
+
+// enumInt() returns the value of 'v' as an 'int' if it has an integer kind.
+func enumInt(v interface{}) (int, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64:
+		return int(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		return int(rv.Uint()), true
+	}
+	return 0, false
+}
+
+// enumName() returns how to show 'v' if its type was registered via
+// RegisterEnum().
+//
+func enumName(v interface{}) (string, bool) {
+	enumMu.RLock()
+	names, ok := enumNames[reflect.TypeOf(v)]
+	enumMu.RUnlock()
+	if !ok {
+		return "", false
+	}
+	n, _ := enumInt(v)
+	name, ok := names[n]
+	if !ok {
+		name = reflect.TypeOf(v).Name()
+	}
+	return fmt.Sprintf("%s(%d)", name, n), true
+}
@@ -0,0 +1,41 @@
+package tutl
+
+import (
+	"os"
+)
+
+// WithEnv() sets the environment variables listed in 'vars', calls 'run',
+// and then restores each of those variables to its prior value (or unsets
+// it if it was not set before).  The variables are restored even if 'run'
+// panics.
+//
+//      tutl.WithEnv(map[string]string{"TZ": "UTC"}, func() {
+//          u.Is("UTC", LoadConfig().TimeZone, "TZ from env")
+//      })
+//
+// Because the environment is global to the process, WithEnv() is not safe
+// to use from tests that run in parallel [via t.Parallel()] with other
+// tests that depend on the same environment variables.
+//
+func WithEnv(vars map[string]string, run func()) {
+	type prior struct {
+		value string
+		isSet bool
+	}
+	saved := make(map[string]prior, len(vars))
+	defer func() {
+		for k, p := range saved {
+			if p.isSet {
+				os.Setenv(k, p.value)
+			} else {
+				os.Unsetenv(k)
+			}
+		}
+	}()
+	for k, v := range vars {
+		old, isSet := os.LookupEnv(k)
+		saved[k] = prior{old, isSet}
+		os.Setenv(k, v)
+	}
+	run()
+}
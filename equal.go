@@ -0,0 +1,69 @@
+package tutl
+
+import (
+	"reflect"
+	"sync"
+)
+
+var (
+	equalMu    sync.RWMutex
+	equalFuncs = map[reflect.Type]func(a, b interface{}) bool{}
+)
+
+// RegisterEqual() registers a custom equality function for a type so that
+// Is() and IsNot() use it, rather than comparing V() strings, whenever
+// both of the values being compared have that type.  'example' can be any
+// value of that type (its value is ignored):
+//
+//      tutl.RegisterEqual(UserID(""), func(a, b interface{}) bool {
+//          return strings.EqualFold(string(a.(UserID)), string(b.(UserID)))
+//      })
+//
+// Failures are still displayed using S().  Registering a type again
+// replaces its function.  Passing a 'nil' 'eq' removes the registration.
+// RegisterEqual() is safe to call concurrently.
+//
+func RegisterEqual(example interface{}, eq func(a, b interface{}) bool) {
+	typ := reflect.TypeOf(example)
+	equalMu.Lock()
+	defer equalMu.Unlock()
+	if nil == eq {
+		delete(equalFuncs, typ)
+		return
+	}
+	equalFuncs[typ] = eq
+}
+
+// same() returns whether 'want' and 'got' are equal, using a function
+// registered via RegisterEqual() or else by comparing V() strings [but see
+// Options.DistinctByteSlices].
+//
+func (o Options) same(want, got interface{}) bool {
+	if o.bytesVsString(want, got) {
+		return false
+	}
+	typ := reflect.TypeOf(want)
+	if nil != typ && typ == reflect.TypeOf(got) {
+		equalMu.RLock()
+		eq, ok := equalFuncs[typ]
+		equalMu.RUnlock()
+		if ok {
+			return eq(want, got)
+		}
+	}
+	return o.V(want) == o.V(got)
+}
+
+// bytesVsString() returns whether Options.DistinctByteSlices is set and
+// one of 'a' and 'b' is a '[]byte' while the other is a 'string'.
+//
+func (o Options) bytesVsString(a, b interface{}) bool {
+	if !o.DistinctByteSlices {
+		return false
+	}
+	_, aStr := a.(string)
+	_, bStr := b.(string)
+	_, aBytes := a.([]byte)
+	_, bBytes := b.([]byte)
+	return aStr && bBytes || aBytes && bStr
+}
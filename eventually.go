@@ -0,0 +1,57 @@
+package tutl
+
+import (
+	"time"
+)
+
+// EventuallyNoError() calls 'run' repeatedly until it returns a 'nil'
+// error or until 'timeout' has passed.  'run' is called right away and
+// then again after each 'interval' of waiting.  This suits waiting for a
+// resource to become ready in an integration test:
+//
+//      tutl.EventuallyNoError("server up", t, 5*time.Second, time.Second/10,
+//          func() error { _, err := http.Get(url); return err })
+//
+// If 'run' never returns 'nil' in time, then a diagnostic similar to
+// "Still got error after 5s for {desc}: {last error}" is displayed (which
+// also causes the unit test to fail).  'run' is always called at least
+// once and a call in progress when the timeout passes is not interrupted.
+// If 'interval' is not positive (which would make this a busy loop), then
+// that is reported as a failure and 'run' is never called.
+//
+// EventuallyNoError() returns whether the test passed.
+//
+func EventuallyNoError(
+	desc string, t TestingT, timeout, interval time.Duration,
+	run func() error,
+) bool {
+	t.Helper()
+	return Default.EventuallyNoError(desc, t, timeout, interval, run)
+}
+
+// See tutl.EventuallyNoError() for documentation.
+func (o Options) EventuallyNoError(
+	desc string, t TestingT, timeout, interval time.Duration,
+	run func() error,
+) (passed bool) {
+	t.Helper()
+	defer o.hooks(desc)(&passed)
+	desc = o.descOf(desc)
+	if interval <= 0 {
+		t.Errorf("EventuallyNoError() needs a positive interval in test code,"+
+			" not %v, for %s.", interval, desc)
+		return false
+	}
+	deadline := time.Now().Add(timeout)
+	for {
+		err := run()
+		if nil == err {
+			return true
+		}
+		if !time.Now().Add(interval).Before(deadline) {
+			t.Errorf("Still got error after %v for %s: %v", timeout, desc, err)
+			return false
+		}
+		time.Sleep(interval)
+	}
+}
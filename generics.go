@@ -0,0 +1,522 @@
+package tutl
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+// optionsOf() returns the Options to use for an assertion that was passed
+// 't'.  If 't' is a TUTL, then its Options are used so that its settings
+// and hooks apply.  Otherwise, 'Default' is used.
+//
+func optionsOf(t TestingT) Options {
+	switch u := t.(type) {
+	case TUTL:
+		return u.o
+	case *TUTL:
+		return u.o
+	}
+	return Default
+}
+
+// SameFunc() calls both 'a' and 'b' for each of the 'inputs' and reports
+// each input for which they return different values.  This is useful for
+// checking that a rewritten function behaves just like the original:
+//
+//      tutl.SameFunc(t, corpus, oldParse, newParse)
+//
+// Both functions must be deterministic (always return the same result
+// for the same input) or spurious failures will be reported.
+//
+// The diagnostic is similar to "Got {b} not {a} for input {in}.\n" where
+// the 3 values are each formatted via S().
+//
+// SameFunc() returns the number of inputs where the results differed.
+//
+func SameFunc[In any, Out comparable](
+	t TestingT, inputs []In, a, b func(In) Out,
+) (failed int) {
+	t.Helper()
+	o := optionsOf(t)
+	defer o.hooksN("")(&failed)
+	lim := o.limitFailures(t)
+	defer lim.done()
+	for _, in := range inputs {
+		want := a(in)
+		got := b(in)
+		if want != got {
+			failed++
+			lim.Error("Got " + o.S(got) + " not " + o.S(want) +
+				" for input " + o.S(in) + ".")
+		}
+	}
+	return failed
+}
+
+// ToStruct() converts 'value' into a 'T' (usually a struct type) so that
+// you can test the resulting fields directly.  If 'value' is a 'string' or
+// '[]byte', then it is taken to be JSON.  Otherwise it is first converted
+// to JSON via json.Marshal().  The JSON is then decoded into a new 'T'.
+//
+//      resp := tutl.ToStruct[Response](body, t)
+//      u.Is(200, resp.Status, "status")
+//
+// If the conversion fails, then a diagnostic is displayed (which also
+// causes the unit test to fail) and the zero value for 'T' is returned.
+//
+func ToStruct[T any](value interface{}, t TestingT) T {
+	t.Helper()
+	var result T
+	var j []byte
+	switch v := value.(type) {
+	case string:
+		j = []byte(v)
+	case []byte:
+		j = v
+	default:
+		var err error
+		if j, err = json.Marshal(value); nil != err {
+			t.Errorf("Can't convert %T to JSON: %v", value, err)
+			return result
+		}
+	}
+	if err := json.Unmarshal(j, &result); nil != err {
+		t.Errorf("Can't convert JSON to %T: %v", result, err)
+	}
+	return result
+}
+
+// JsonRoundTrips() tests that converting 'value' to JSON and then back
+// into a new 'T' produces a value that is reflect.DeepEqual() to 'value'.
+// This can catch bugs in custom MarshalJSON() or UnmarshalJSON() methods
+// and fields that get lost due to 'json' tags.
+//
+// If the round trip fails or changes the value, then a diagnostic is
+// displayed which also causes the unit test to fail.  The diagnostic is
+// similar to "Got {after} not {value} for {desc}." (where each value is
+// formatted via "%+v") followed by the intermediate JSON.
+//
+// JsonRoundTrips() returns whether the test passed.
+//
+func JsonRoundTrips[T any](value T, desc string, t TestingT) (passed bool) {
+	t.Helper()
+	o := optionsOf(t)
+	defer o.hooks(desc)(&passed)
+	desc = o.descOf(desc)
+	j, err := json.Marshal(value)
+	if nil != err {
+		t.Errorf("Can't convert %T to JSON for %s: %v", value, desc, err)
+		return false
+	}
+	var after T
+	if err := json.Unmarshal(j, &after); nil != err {
+		t.Errorf("Can't convert JSON back to %T for %s: %v", after, desc, err)
+		t.Logf("(JSON was %s)", j)
+		return false
+	}
+	if reflect.DeepEqual(value, after) {
+		return true
+	}
+	o.gotNot(fmt.Sprintf("%+v", after), fmt.Sprintf("%+v", value), desc, t)
+	t.Logf("(JSON was %s)", j)
+	return false
+}
+
+// GobRoundTrips() tests that encoding 'value' via 'encoding/gob' and then
+// decoding it into a new 'T' produces a value that is reflect.DeepEqual()
+// to 'value'.  This can catch values that gob can't handle, such as types
+// that were not registered via gob.Register() or data held in unexported
+// fields (which gob silently drops).
+//
+// If the round trip fails or changes the value, then a diagnostic is
+// displayed which also causes the unit test to fail.  The diagnostic is
+// similar to "Got {after} not {value} for {desc}." (where each value is
+// formatted via "%+v").
+//
+// GobRoundTrips() returns whether the test passed.
+//
+func GobRoundTrips[T any](value T, desc string, t TestingT) (passed bool) {
+	t.Helper()
+	o := optionsOf(t)
+	defer o.hooks(desc)(&passed)
+	desc = o.descOf(desc)
+	buf := new(bytes.Buffer)
+	if err := gob.NewEncoder(buf).Encode(&value); nil != err {
+		t.Errorf("Can't gob-encode %T for %s: %v", value, desc, err)
+		return false
+	}
+	var after T
+	if err := gob.NewDecoder(buf).Decode(&after); nil != err {
+		t.Errorf("Can't gob-decode %T for %s: %v", after, desc, err)
+		return false
+	}
+	if reflect.DeepEqual(value, after) {
+		return true
+	}
+	o.gotNot(fmt.Sprintf("%+v", after), fmt.Sprintf("%+v", value), desc, t)
+	return false
+}
+
+// IsStable() calls 'fn' 'n' times and tests that every call returns the
+// same value as the first call.  This can expose hidden nondeterminism,
+// such as output that depends on the (random) iteration order of a map.
+// 'fn' is expected to be deterministic; IsStable() is how you test that.
+//
+// If a call returns a different value, then a diagnostic is displayed
+// which also causes the unit test to fail.  The diagnostic is similar to
+// "Got {value} from call 5 not {first} for {desc}." where the values are
+// formatted via S().  Only the first such call is reported.  If 'n' is
+// not positive, then that is reported as a failure and 'fn' is not called.
+//
+// IsStable() returns whether the test passed.
+//
+func IsStable[T comparable](
+	desc string, t TestingT, n int, fn func() T,
+) (passed bool) {
+	t.Helper()
+	o := optionsOf(t)
+	defer o.hooks(desc)(&passed)
+	desc = o.descOf(desc)
+	if n < 1 {
+		t.Errorf("IsStable() needs a positive call count in test code,"+
+			" not %d, for %s.", n, desc)
+		return false
+	}
+	first := fn()
+	for i := 2; i <= n; i++ {
+		if v := fn(); v != first {
+			o.gotNot(o.S(v)+fmt.Sprintf(" from call %d", i), o.S(first),
+				desc, t)
+			return false
+		}
+	}
+	return true
+}
+
+// ThatT() is the same as tutl.That() except that 'pred' takes a 'T' rather
+// than an 'interface{}' value:
+//
+//      tutl.ThatT(n, "n is even", t, func(v int) bool { return 0 == v%2 })
+//
+func ThatT[T any](got T, desc string, t TestingT, pred func(T) bool) bool {
+	t.Helper()
+	return optionsOf(t).That(got, desc, t, func(interface{}) bool {
+		return pred(got)
+	})
+}
+
+// CallRecorder() returns a 'record' function that can be passed as a
+// callback and that records each value it is called with, and a 'calls'
+// function that returns (a copy of) all of the recorded values, in order.
+// This makes it easy to test what arguments a callback gets passed:
+//
+//      record, calls := tutl.CallRecorder[string]()
+//      Walk(tree, record)
+//      u.Is(`[a b c]`, calls(), "visited")
+//
+// To record several arguments, use a struct type for 'T' and wrap 'record'
+// in a small closure.  Both functions are safe to call concurrently.
+//
+func CallRecorder[T any]() (record func(T), calls func() []T) {
+	var mu sync.Mutex
+	var recorded []T
+	record = func(v T) {
+		mu.Lock()
+		defer mu.Unlock()
+		recorded = append(recorded, v)
+	}
+	calls = func() []T {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]T(nil), recorded...)
+	}
+	return record, calls
+}
+
+// CalledTimes() tests that the 'calls' function returned by CallRecorder()
+// reports exactly 'want' recorded calls:
+//
+//      record, calls := tutl.CallRecorder[Event]()
+//      bus.Subscribe(record)
+//      bus.Publish(Event{ID: 1})
+//      tutl.CalledTimes(1, calls, "events delivered", t)
+//
+// If the count differs, then a diagnostic similar to "Got 2 calls not 1
+// for {desc}." is displayed (which also causes the unit test to fail) and
+// the recorded arguments are logged, each formatted via S().
+//
+// CalledTimes() returns whether the test passed.
+//
+func CalledTimes[T any](
+	want int, calls func() []T, desc string, t TestingT,
+) (passed bool) {
+	t.Helper()
+	o := optionsOf(t)
+	defer o.hooks(desc)(&passed)
+	desc = o.descOf(desc)
+	got := calls()
+	if want == len(got) {
+		return true
+	}
+	t.Errorf("Got %d calls not %d for %s.", len(got), want, desc)
+	for i, v := range got {
+		t.Logf("    call %d: %s", i+1, o.ReplaceNewlines(o.S(v)))
+	}
+	return false
+}
+
+// DeepCopy() returns a copy of 'v' that shares no memory with 'v' (as far
+// as possible) so that tests can snapshot fixture data before changing it.
+//
+//      before := tutl.DeepCopy(cfg)
+//      Normalize(&cfg)
+//
+// The copy is made via reflection so it keeps the types of values held in
+// 'interface{}' types (unlike a JSON round-trip).  Exported struct fields,
+// pointers, slices, arrays, and maps are copied deeply.  Unexported struct
+// fields are kept but copied only shallowly (so any memory they point to
+// is shared).  Channels and functions are shared rather than copied.
+//
+// DeepCopy() is a best effort and so never fails or reports an error.  A
+// value that refers to itself (such as via a pointer cycle) is copied such
+// that the copy has the same cycle.
+//
+func DeepCopy[T any](v T) T {
+	var result T
+	val := reflect.ValueOf(&v).Elem()
+	reflect.ValueOf(&result).Elem().Set(copier{}.copy(val))
+	return result
+}
+
+// copied identifies a pointer, map, or slice that DeepCopy() has already
+// copied.
+//
+type copied struct {
+	addr uintptr
+	len  int
+	typ  reflect.Type
+}
+
+// copier makes reflection-based deep copies for DeepCopy(), remembering the
+// copy made for each pointer, map, and slice so that cycles (and shared
+// references) are preserved rather than followed forever.
+//
+type copier map[copied]reflect.Value
+
+// copy() returns a deep copy of 'v'.
+func (cp copier) copy(v reflect.Value) reflect.Value {
+	c := reflect.New(v.Type()).Elem()
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			key := copied{v.Pointer(), 0, v.Type()}
+			if prior, ok := cp[key]; ok {
+				return prior
+			}
+			p := reflect.New(v.Type().Elem())
+			cp[key] = p
+			p.Elem().Set(cp.copy(v.Elem()))
+			c.Set(p)
+		}
+	case reflect.Interface:
+		if !v.IsNil() {
+			c.Set(cp.copy(v.Elem()))
+		}
+	case reflect.Slice:
+		if !v.IsNil() {
+			key := copied{v.Pointer(), v.Len(), v.Type()}
+			if prior, ok := cp[key]; ok {
+				return prior
+			}
+			c.Set(reflect.MakeSlice(v.Type(), v.Len(), v.Len()))
+			cp[key] = c
+			for i := 0; i < v.Len(); i++ {
+				c.Index(i).Set(cp.copy(v.Index(i)))
+			}
+		}
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(cp.copy(v.Index(i)))
+		}
+	case reflect.Map:
+		if !v.IsNil() {
+			key := copied{v.Pointer(), 0, v.Type()}
+			if prior, ok := cp[key]; ok {
+				return prior
+			}
+			c.Set(reflect.MakeMapWithSize(v.Type(), v.Len()))
+			cp[key] = c
+			iter := v.MapRange()
+			for iter.Next() {
+				c.SetMapIndex(cp.copy(iter.Key()), cp.copy(iter.Value()))
+			}
+		}
+	case reflect.Struct:
+		c.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if "" == v.Type().Field(i).PkgPath {
+				c.Field(i).Set(cp.copy(v.Field(i)))
+			}
+		}
+	default:
+		c.Set(v)
+	}
+	return c
+}
+
+// ForEach() runs the same checks against several implementations of an
+// interface.  For each entry in 'impls' (in order of name), it calls
+// 't.Run(name, ...)' and passes 'run' a TUTL for the sub-test along with
+// the implementation:
+//
+//      tutl.ForEach(t, map[string]Cache{
+//          "memory": NewMemCache(),
+//          "disk":   NewDiskCache(dir),
+//      }, func(u tutl.TUTL, c Cache) {
+//          c.Put("k", "v")
+//          u.Is("v", c.Get("k"), "get after put")
+//      })
+//
+// 'T' should usually be the interface type and 'run' should only use the
+// methods of that interface, so that every implementation is held to the
+// same contract.
+//
+func ForEach[T any](
+	t *testing.T, impls map[string]T, run func(u TUTL, impl T),
+) {
+	t.Helper()
+	names := make([]string, 0, len(impls))
+	for name := range impls {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		impl := impls[name]
+		t.Run(name, func(t *testing.T) {
+			run(New(t), impl)
+		})
+	}
+}
+
+// IsSliceBy() tests that 'want' and 'got' have the same length and that
+// 'eq' returns 'true' for each pair of corresponding elements.  This lets
+// the caller decide what matters when comparing elements:
+//
+//      tutl.IsSliceBy(want, got, "users", t, func(a, b User) bool {
+//          return a.ID == b.ID && a.Name == b.Name // Ignore timestamps.
+//      })
+//
+// If the lengths differ, then a diagnostic similar to "Got length 3 not 2
+// for {desc}." is displayed.  Otherwise, the first pair of elements for
+// which 'eq' returns 'false' is reported via a diagnostic similar to "Got
+// {got[i]} not {want[i]} at index {i} for {desc}." where S() is used for
+// the elements.  Either also causes the unit test to fail.
+//
+// IsSliceBy() returns whether the test passed.
+//
+func IsSliceBy[T any](
+	want, got []T, desc string, t TestingT, eq func(a, b T) bool,
+) (passed bool) {
+	t.Helper()
+	o := optionsOf(t)
+	defer o.hooks(desc)(&passed)
+	desc = o.descOf(desc)
+	if len(want) != len(got) {
+		t.Errorf("Got length %d not %d for %s.", len(got), len(want), desc)
+		return false
+	}
+	for i := range want {
+		if !eq(want[i], got[i]) {
+			t.Errorf("Got %s not %s at index %d for %s.",
+				o.ReplaceNewlines(o.S(got[i])),
+				o.ReplaceNewlines(o.S(want[i])), i, desc)
+			return false
+		}
+	}
+	return true
+}
+
+// DrainChan() receives values from 'ch' until it is closed or until
+// 'timeout' has passed since DrainChan() was called, whichever comes first,
+// and returns all of the values received (in order) so they can then be
+// checked:
+//
+//      got := tutl.DrainChan(producer.Out(), time.Second, t)
+//      u.Is("[1 2 3]", got, "produced")
+//
+// If the timeout is reached before 'ch' is closed, then a diagnostic
+// similar to "Channel not closed after 1s (got 2 values)." is displayed
+// (which also causes the unit test to fail) and the values received so
+// far are returned.
+//
+func DrainChan[T any](ch <-chan T, timeout time.Duration, t TestingT) []T {
+	t.Helper()
+	passed := true
+	defer optionsOf(t).hooks("")(&passed)
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	var got []T
+	for {
+		select {
+		case v, ok := <-ch:
+			if !ok {
+				return got
+			}
+			got = append(got, v)
+		case <-timer.C:
+			passed = false
+			t.Errorf("Channel not closed after %v (got %d values).",
+				timeout, len(got))
+			return got
+		}
+	}
+}
+
+// IsStreamSorted() compares two (usually sorted) sequences one element at
+// a time without holding either in memory.  'want' and 'got' each return
+// the next element and 'true', or 'false' once there are no more elements.
+// They are advanced in lockstep until both end or they diverge:
+//
+//      tutl.IsStreamSorted(expected.Next, cursor.Next, "all rows", t)
+//
+// The first divergence is reported via a diagnostic similar to "Got {got}
+// at position 7 not {want} for {desc}." (where positions start at 0) and
+// which also causes the unit test to fail.  If one sequence ends early,
+// then "end of stream" is shown in place of its element.
+//
+// IsStreamSorted() returns whether the test passed.
+//
+func IsStreamSorted[T comparable](
+	want, got func() (T, bool), desc string, t TestingT,
+) (passed bool) {
+	t.Helper()
+	o := optionsOf(t)
+	defer o.hooks(desc)(&passed)
+	desc = o.descOf(desc)
+	for pos := 0; ; pos++ {
+		w, wOk := want()
+		g, gOk := got()
+		if !wOk && !gOk {
+			return true
+		}
+		if wOk && gOk && w == g {
+			continue
+		}
+		sWant, sGot := "end of stream", "end of stream"
+		if wOk {
+			sWant = o.S(w)
+		}
+		if gOk {
+			sGot = o.S(g)
+		}
+		o.gotNot(sGot+fmt.Sprintf(" at position %d", pos), sWant, desc, t)
+		return false
+	}
+}
@@ -1,9 +1,18 @@
 package tutl
 
 import (
+	"errors"
 	"fmt"
+	"math"
+	"path/filepath"
+	"reflect"
 	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
+	"unicode"
 	"unicode/utf8"
 )
 
@@ -38,16 +47,35 @@ type Options struct {
 	//
 	// If the diagnostic line is no longer than LineWidth but is longer than
 	// LineWidth-PathLength, then a newline gets prepended to it as the
-	// prepended source info would likely cause the diagnostic to wrap.
+	// prepended source info would likely cause the diagnostic to wrap
+	// [unless NoLeadingNewline is set].
 	//
 	LineWidth int
 
+	// NoLeadingNewline, if set, turns off the prepending of a newline that
+	// is described for LineWidth.  So a diagnostic line that is no longer
+	// than LineWidth is always output as a single line right after the
+	// source info.  Only that one case changes; a line longer than
+	// LineWidth (or containing a newline) is still split into "\nGot
+	// ...\nnot ...\nfor ...".  This gives more predictable output for log
+	// viewers that show the source info elsewhere or dislike blank lines.
+	//
+	NoLeadingNewline bool
+
 	// PathLength is the maximum expected length of the path to the
 	// *_test.go file being run plus the line number that 'go test'
 	// prepends to each diagnostic.  It defaults to 20.
 	//
 	PathLength int
 
+	// MeasurePath, if set, makes the length of the "file.go:123: " prefix
+	// that 'go test' prepends to each diagnostic be measured rather than
+	// estimated via PathLength.  The prefix is computed from the first
+	// caller (found via runtime.Caller()) that is in a *_test.go file.  If
+	// no such caller is found, then PathLength is used.
+	//
+	MeasurePath bool
+
 	// Digits32 specifies how many significant digits to use when comparing
 	// 'float32' values.  In particular, if a 'float32' or '[]float32' value
 	// is passed to V(), then no more than Digits32 significant digits are
@@ -75,6 +103,171 @@ type Options struct {
 	// accurate to only slightly less than 16 digits).
 	//
 	Digits64 int
+
+	// ThousandsSep, if not 0, is inserted between each group of 3 digits
+	// when S() converts an integer value (other than a 'byte') into a
+	// string.  For example, setting it to ',' makes S(1048576) return
+	// "1,048,576" while setting it to '_' would return "1_048_576".
+	//
+	// V() is not impacted so Is() and similar still compare the plain
+	// digits; ThousandsSep only changes how values are displayed in
+	// diagnostics.  It defaults to 0 (no separators).
+	//
+	ThousandsSep rune
+
+	// MaxDescLen, if positive, is the maximum number of characters of
+	// each 'desc' that Is(), IsNot(), and Circa() include in their
+	// diagnostics.  A longer 'desc' is truncated to end in "..." and the
+	// full 'desc' is then logged on a separate line after the diagnostic.
+	// Since the truncated 'desc' is what is used when deciding whether to
+	// split a diagnostic over several lines [see LineWidth], this can keep
+	// verbose descriptions from causing otherwise short diagnostics to be
+	// split.  It defaults to 0 (no truncation).
+	//
+	MaxDescLen int
+
+	// DescTransform, if not 'nil', is called on each 'desc' before it is
+	// used in any diagnostic.  This lets a project rewrite descriptions
+	// centrally, such as to strip a long prefix that many descriptions
+	// share:
+	//
+	//      u.SetDescTransform(func(d string) string {
+	//          return strings.TrimPrefix(d, "https://api.example.com/v2")
+	//      })
+	//
+	// The transform is applied before MaxDescLen truncation.
+	//
+	DescTransform func(string) string
+
+	// BeforeAssert, if not 'nil', is called with the 'desc' (before any
+	// DescTransform is applied) at the start of each assertion.
+	// AfterAssert, if not 'nil', is called at the end of each assertion
+	// (so after any diagnostics have been reported) along with whether
+	// the assertion passed.  These make it easy to count, time, or log
+	// assertions.
+	//
+	// The hooks are called by the Options methods (and so by the TUTL
+	// methods and non-method functions) that test something and that
+	// take a 'desc' argument, such as Is(), IsNot(), HasType(), Circa(),
+	// Like(), and IsEmpty().  An assertion that is implemented using
+	// another assertion [like HasType() using Is()] only calls them once.
+	//
+	BeforeAssert func(desc string)
+	AfterAssert  func(desc string, passed bool)
+
+	// Verbose, if set, makes Is(), IsNot(), HasType(), and Circa() each
+	// log a line like "OK: Got {got} for {desc}." when they pass.  This can
+	// help when debugging a test that passes for the wrong reason.  It
+	// defaults to 'false'.
+	//
+	Verbose bool
+
+	// MaxFailures, if positive, limits how many failures are reported by a
+	// single call to an assertion that can report several failures, such
+	// as Like(), SameSet(), JsonFields(), RegexpMatches(), StructDiff(),
+	// or SameFunc().  After that many failures are reported, the rest are
+	// not reported individually; instead, a note similar to "...and 12
+	// more failures" is logged.  The count of failures returned by such
+	// assertions is not capped.
+	//
+	MaxFailures int
+
+	// TrimLikeInput, if set, makes Like() remove leading and trailing white
+	// space from the string it checks before checking any of the matches.
+	// This is handy when matching against captured output that ends with
+	// a newline.  It defaults to 'false'.
+	//
+	TrimLikeInput bool
+
+	// RecurseDirs, if set, makes DirHas() list all of the files under the
+	// directory rather than just the entries at its top level.
+	//
+	RecurseDirs bool
+
+	// MaxDepth limits how deeply StructDiff() recurses into nested values,
+	// which keeps it from looping forever on a self-referential value.  If
+	// set to 0, a limit of 50 is used.  If negative, there is no limit.
+	//
+	MaxDepth int
+
+	// DistinctByteSlices, if set, makes Is() consider a '[]byte' and a
+	// 'string' to be different even if they hold the same bytes.  Such a
+	// failure shows the type of each value.  It defaults to 'false' (V()
+	// converts a '[]byte' to a 'string' so they compare equal).
+	//
+	DistinctByteSlices bool
+
+	// TimesInUTC, if set, makes S() convert each 'time.Time' value to UTC
+	// before formatting it (via its String() method).  This makes
+	// diagnostics the same no matter what time zone the tests run in and
+	// also drops any monotonic clock reading (like "m=+0.0123") from the
+	// output.  Only how times are displayed changes; V() is not changed so
+	// two times that are the same instant but in different time zones
+	// still compare as different in Is().  It defaults to 'true'.
+	//
+	TimesInUTC bool
+
+	// RawOutput, if set, makes S() just convert each value via fmt.Sprint()
+	// (except that '[]byte' values are still treated as 'string's).  So no
+	// quotes are added and no characters are escaped.  This can be useful
+	// when using FakeTester to build human-facing reports, but it makes
+	// test diagnostics less safe (control characters are output as-is and
+	// it can be unclear where a value starts or ends).  It defaults to
+	// 'false'.
+	//
+	RawOutput bool
+
+	// StrictErrorCase makes ErrorFormat() complain about any error message
+	// that starts with an uppercase letter.  By default, a message can
+	// start with an uppercase letter if the next character is also
+	// uppercase, as in an acronym like "EOF" or "HTTP".
+	//
+	StrictErrorCase bool
+
+	// ShowOriginal, if set, makes a failing IsAfter() also log the values
+	// from before normalization, since the difference that mattered may
+	// not be obvious from the normalized values.  It defaults to 'true'.
+	//
+	ShowOriginal bool
+
+	// CircaTruncates, if set, makes Circa() truncate values to the
+	// requested number of significant digits rather than round them.  See
+	// Circa() for how this changes which values are considered equal.  It
+	// defaults to 'false'.
+	//
+	CircaTruncates bool
+
+	// BoolWords holds the words that S() uses to show 'false' and 'true'
+	// values, in that order, such as {"disabled", "enabled"}.  This only
+	// changes how 'bool' values are displayed.  V() still converts them to
+	// "false" and "true" so Is() and other comparisons are not affected
+	// (and Is(true, "true", ...) still passes).  An empty word means to use
+	// "false" or "true".  It defaults to {"false", "true"}.
+	//
+	BoolWords [2]string
+
+	// SnapshotDir is the directory that holds the files used by
+	// SnapshotValue().  An empty value means "testdata" (relative to the
+	// current directory, which is the package's directory during 'go
+	// test').
+	//
+	SnapshotDir string
+
+	// QuoteLoneString controls whether S() puts double quotes around a
+	// 'string' when it is the only argument passed to it.  Since Is() and
+	// other assertions use S() to show 'got' and 'want' values, turning
+	// this off also makes them show such 'string' values without quotes
+	// (control characters are still escaped).  It is 'true' in
+	// 'tutl.Default'.
+	//
+	QuoteLoneString bool
+
+	// HumanizeBytes controls whether S() displays 'tutl.Bytes' values
+	// like "1.5 MiB" [see HumanBytes()].  V() is not impacted so the raw
+	// byte counts are still what get compared.  It is 'true' in
+	// 'tutl.Default'.
+	//
+	HumanizeBytes bool
 }
 
 const MaxDigits32 = 7
@@ -84,7 +277,10 @@ const MaxDigits64 = 15
 // you make a copy and use it, such as via New() (see Options for more).
 //
 var Default = Options{
-	doNotEscape: '\n', LineWidth: 72, PathLength: 20, Digits32: 5, Digits64: 12}
+	doNotEscape: '\n', LineWidth: 72, PathLength: 20, Digits32: 5, Digits64: 12,
+	HumanizeBytes: true, QuoteLoneString: true, MaxDepth: 50,
+	TimesInUTC: true, ShowOriginal: true,
+	BoolWords: [2]string{"false", "true"}}
 
 // V() just converts a value to a string.  It is similar to 'fmt.Sprint(v)'.
 // But it treats '[]byte' values as 'string's.  It also (by default) uses
@@ -134,6 +330,204 @@ func (o Options) V(v interface{}) string {
 	return fmt.Sprint(v)
 }
 
+// sepThousands() inserts o.ThousandsSep between groups of 3 digits in
+// the decimal integer string 's' (if ThousandsSep is not 0).
+//
+func (o Options) sepThousands(s string) string {
+	if 0 == o.ThousandsSep {
+		return s
+	}
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign = "-"
+		s = s[1:]
+	}
+	if len(s) <= 3 {
+		return sign + s
+	}
+	sep := string(o.ThousandsSep)
+	lead := len(s) % 3
+	if 0 == lead {
+		lead = 3
+	}
+	parts := []string{s[:lead]}
+	for i := lead; i < len(s); i += 3 {
+		parts = append(parts, s[i:i+3])
+	}
+	return sign + strings.Join(parts, sep)
+}
+
+// Bytes is a count of bytes.  Converting a count to 'tutl.Bytes' before
+// passing it to Is() (or similar) makes any diagnostic show the count
+// like "1.5 MiB" rather than "1572864" [see Options.HumanizeBytes].
+//
+//      u.Is(tutl.Bytes(1<<20), tutl.Bytes(len(buf)), "buffer size")
+//
+type Bytes int64
+
+var byteUnits = []string{"KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+
+// HumanBytes() returns a byte count as a short string like "1.5 MiB" or
+// "512 B".  Units are powers of 1024 and at most 2 digits are shown after
+// the decimal point.
+//
+func HumanBytes(n int64) string {
+	sign := ""
+	u := uint64(n)
+	if n < 0 {
+		sign = "-"
+		u = uint64(-n)
+	}
+	if u < 1024 {
+		return fmt.Sprintf("%s%d B", sign, u)
+	}
+	f := float64(u)
+	unit := ""
+	for _, unit = range byteUnits {
+		f /= 1024
+		if f < 1024 {
+			break
+		}
+	}
+	num := strconv.FormatFloat(f, 'f', 2, 64)
+	num = strings.TrimRight(strings.TrimRight(num, "0"), ".")
+	return sign + num + " " + unit
+}
+
+// hooks() calls o.BeforeAssert (if set) and returns a function to be
+// deferred that calls o.AfterAssert (if set).  Use it like:
+//
+//      defer o.hooks(desc)(&passed)
+//
+func (o Options) hooks(desc string) func(*bool) {
+	if nil != o.BeforeAssert {
+		o.BeforeAssert(desc)
+	}
+	return func(passed *bool) {
+		if nil != o.AfterAssert {
+			o.AfterAssert(desc, *passed)
+		}
+	}
+}
+
+// hooksN() is like hooks() but for assertions that return a count of
+// failures.
+//
+func (o Options) hooksN(desc string) func(*int) {
+	after := o.hooks(desc)
+	return func(failures *int) {
+		passed := 0 == *failures
+		after(&passed)
+	}
+}
+
+// noHooks() returns a copy of the Options without any hooks set.  This
+// is used when one assertion is implemented by calling another so that
+// the hooks only get called once.
+//
+func (o Options) noHooks() Options {
+	o.BeforeAssert = nil
+	o.AfterAssert = nil
+	return o
+}
+
+// failLimiter wraps a TestingT so that at most 'max' failures get reported
+// through it [see Options.MaxFailures].  Any output logged after a failure
+// that was not reported is also suppressed.
+//
+type failLimiter struct {
+	TestingT
+	max, failures int
+}
+
+// limitFailures() returns a failLimiter that reports to 't'.  Call done()
+// on it once all of the failures have been reported.
+//
+func (o Options) limitFailures(t TestingT) *failLimiter {
+	return &failLimiter{TestingT: t, max: o.MaxFailures}
+}
+
+// quiet() returns whether output is currently being suppressed.
+func (l *failLimiter) quiet() bool {
+	return 0 < l.max && l.max < l.failures
+}
+
+func (l *failLimiter) Error(args ...interface{}) {
+	l.TestingT.Helper()
+	if l.failures++; !l.quiet() {
+		l.TestingT.Error(args...)
+	}
+}
+
+func (l *failLimiter) Errorf(format string, args ...interface{}) {
+	l.TestingT.Helper()
+	if l.failures++; !l.quiet() {
+		l.TestingT.Errorf(format, args...)
+	}
+}
+
+func (l *failLimiter) Log(args ...interface{}) {
+	l.TestingT.Helper()
+	if !l.quiet() {
+		l.TestingT.Log(args...)
+	}
+}
+
+func (l *failLimiter) Logf(format string, args ...interface{}) {
+	l.TestingT.Helper()
+	if !l.quiet() {
+		l.TestingT.Logf(format, args...)
+	}
+}
+
+// done() logs how many failures were not reported (if any).
+func (l *failLimiter) done() {
+	l.TestingT.Helper()
+	if l.quiet() {
+		l.TestingT.Logf("...and %d more failures", l.failures-l.max)
+	}
+}
+
+// maxDepth() returns the recursion limit to use [see Options.MaxDepth],
+// where 0 means no limit.
+//
+func (o Options) maxDepth() int {
+	if 0 == o.MaxDepth {
+		return 50
+	} else if o.MaxDepth < 0 {
+		return 0
+	}
+	return o.MaxDepth
+}
+
+// descOf() returns 'desc' as rewritten by o.DescTransform (if set).
+func (o Options) descOf(desc string) string {
+	if nil == o.DescTransform {
+		return desc
+	}
+	return o.DescTransform(desc)
+}
+
+// shortDesc() returns 'desc' truncated to at most o.MaxDescLen characters.
+func (o Options) shortDesc(desc string) string {
+	if o.MaxDescLen <= 0 || utf8.RuneCountInString(desc) <= o.MaxDescLen {
+		return desc
+	}
+	r := []rune(desc)
+	if o.MaxDescLen <= 3 {
+		return string(r[:o.MaxDescLen])
+	}
+	return string(r[:o.MaxDescLen-3]) + "..."
+}
+
+// logFullDesc() logs the full 'desc' if shortDesc() had to truncate it.
+func (o Options) logFullDesc(short, desc string, t TestingT) {
+	t.Helper()
+	if short != desc {
+		t.Log("(Full desc: " + desc + ")")
+	}
+}
+
 // DoubleQuote() returns the string enclosed in double quotes and with
 // contained \ and " characters escaped.
 //
@@ -244,10 +638,15 @@ func GetPanic(run func()) (failure interface{}) {
 // EscapeNewline()].  S() also escapes non-UTF-8 byte sequences.
 //
 // If S() is passed a single argument that is a 'string', then it will put
-// double quotes around it and escape any contained " and \ characters.
+// double quotes around it and escape any contained " and \ characters
+// [unless Options.QuoteLoneString is turned off].
 //
 // See V() for how 'float32', 'float64', '[]float32', or '[]float64' values
-// are converted.
+// are converted.  See Options.ThousandsSep and Options.HumanizeBytes for
+// how to make large integers easier to read.  See RegisterEnum() for how
+// to show the names of enumeration values.  See Options.BoolWords for how
+// to show 'bool' values using other words.  See Options.RawOutput for how
+// to turn off all quoting and escaping.
 //
 // Note that S() does not put single quotes around 'rune' values as 'rune'
 // is just an alias for 'int32' so S('x') == S(int32('x')) == "120" while
@@ -259,6 +658,9 @@ func S(vs ...interface{}) string {
 
 // See tutl.S() for documentation.
 func (o Options) S(vs ...interface{}) string {
+	if o.RawOutput {
+		return rawString(vs)
+	}
 	ss := make([]string, len(vs))
 	for j, ix := range vs {
 		s := ""
@@ -270,15 +672,35 @@ func (o Options) S(vs ...interface{}) string {
 		case []byte:
 			s = DoubleQuote(string(v))
 		case string:
-			if 1 == len(vs) {
+			if 1 == len(vs) && o.QuoteLoneString {
 				s = DoubleQuote(v)
 			} else {
 				s = v
 			}
 		case float32, float64, []float32, []float64:
 			s = o.V(ix)
+		case time.Time:
+			if o.TimesInUTC {
+				v = v.UTC()
+			}
+			s = v.String()
+		case int, int8, int16, int32, int64,
+			uint, uint16, uint32, uint64, uintptr:
+			s = o.sepThousands(fmt.Sprint(ix))
+		case bool:
+			s = o.boolWord(v)
+		case Bytes:
+			if o.HumanizeBytes {
+				s = HumanBytes(int64(v))
+			} else {
+				s = o.sepThousands(fmt.Sprint(int64(v)))
+			}
 		default:
-			s = fmt.Sprintf("%v", ix)
+			if name, ok := enumName(ix); ok {
+				s = name
+			} else {
+				s = fmt.Sprintf("%v", ix)
+			}
 		}
 		buf := make([]byte, 0, len(s))
 		for i, r := range s {
@@ -295,14 +717,42 @@ func (o Options) S(vs ...interface{}) string {
 	return strings.Join(ss, "")
 }
 
+// boolWord() returns how S() shows 'b' [see Options.BoolWords].
+func (o Options) boolWord(b bool) string {
+	i := 0
+	if b {
+		i = 1
+	}
+	if "" == o.BoolWords[i] {
+		return strconv.FormatBool(b)
+	}
+	return o.BoolWords[i]
+}
+
+// rawString() formats 'vs' for S() when Options.RawOutput is set.
+func rawString(vs []interface{}) string {
+	ss := make([]string, len(vs))
+	for i, v := range vs {
+		if b, ok := v.([]byte); ok {
+			ss[i] = string(b)
+		} else {
+			ss[i] = fmt.Sprint(v)
+		}
+	}
+	return strings.Join(ss, "")
+}
+
 // Is() tests that the first two arguments are converted to the same string
 // by V().  If they are not, then a diagnostic is displayed which also causes
-// the unit test to fail.
+// the unit test to fail.  [But see RegisterEqual() for how to compare
+// values of specific types differently.]
 //
 // The diagnostic is similar to "Got {got} not {want} for {desc}.\n" except
 // that; 1) S() is used for 'got' and 'want' so control characters will be
 // escaped and their values may be in quotes and 2) it will be split onto
-// multiple lines if the values involved are long enough.
+// multiple lines if the values involved are long enough.  If S() shows the
+// two values the same (such as two Bytes values that round to "1.5 MiB"),
+// then each is followed by its V() string, like "1.5 MiB (1572865)".
 //
 // Note that you pass 'want' before 'got' when calling Is() because the
 // 'want' value is often a simple constant while 'got' can be a complex
@@ -320,32 +770,103 @@ func Is(want, got interface{}, desc string, t TestingT) bool {
 }
 
 // See tutl.Is() for documentation.
-func (o Options) Is(want, got interface{}, desc string, t TestingT) bool {
+func (o Options) Is(
+	want, got interface{}, desc string, t TestingT,
+) (passed bool) {
 	t.Helper()
-	vwant := o.V(want)
-	vgot := o.V(got)
-	if vwant == vgot {
-		//  t.Log("want:", vwant, " got:", vgot, " for:", desc)
+	defer o.hooks(desc)(&passed)
+	desc = o.descOf(desc)
+	if o.same(want, got) {
+		if o.Verbose {
+			t.Log("OK: Got " + o.ReplaceNewlines(o.S(got)) + " for " + desc + ".")
+		}
 		return true
 	}
-	sGot := o.S(got)
-	sWant := o.S(want)
-	line := "Got " + sGot + " not " + sWant + " for " + desc + "."
+	sGot, sWant := o.S(got), o.S(want)
+	if o.bytesVsString(want, got) {
+		if _, ok := got.(string); ok {
+			sGot, sWant = sGot+" (string)", sWant+" ([]byte)"
+		} else {
+			sGot, sWant = sGot+" ([]byte)", sWant+" (string)"
+		}
+	} else if vGot, vWant := o.V(got), o.V(want); sGot == sWant &&
+		vGot != vWant {
+		// S() lost the difference (such as by humanizing Bytes values):
+		sGot += " (" + o.ReplaceNewlines(vGot) + ")"
+		sWant += " (" + o.ReplaceNewlines(vWant) + ")"
+	}
+	o.gotNot(sGot, sWant, desc, t)
+	return false
+}
+
+// gotNot() reports a failure via a diagnostic similar to "Got {sGot} not
+// {sWant} for {desc}." but that may be split onto multiple lines [see
+// Options.LineWidth].  It is used by Is() and similar assertions.
+//
+func (o Options) gotNot(sGot, sWant, desc string, t TestingT) {
+	t.Helper()
+	short := o.shortDesc(desc)
+	line := "Got " + sGot + " not " + sWant + " for " + short + "."
 	wid := utf8.RuneCountInString(line)
 	if strings.Contains(line, "\n") {
 		sGot = o.ReplaceNewlines(sGot)
 		sWant = o.ReplaceNewlines(sWant)
-		t.Errorf("\nGot %s\nnot %s\nfor %s.", sGot, sWant, desc)
-		return false
-	}
-	if wid <= o.LineWidth-o.PathLength {
+		t.Errorf("\nGot %s\nnot %s\nfor %s.", sGot, sWant, short)
+	} else if wid <= o.LineWidth && o.NoLeadingNewline {
+		t.Error(line)
+	} else if wid <= o.LineWidth-o.pathLength() {
 		t.Error(line)
 	} else if wid <= o.LineWidth {
 		t.Error("\n" + line)
 	} else {
-		t.Errorf("\nGot %s\nnot %s\nfor %s.", sGot, sWant, desc)
+		t.Errorf("\nGot %s\nnot %s\nfor %s.", sGot, sWant, short)
 	}
-	return false
+	o.logFullDesc(short, desc, t)
+}
+
+// pathLength() returns the PathLength to use, measuring the length of
+// the source info that 'go test' will prepend if MeasurePath is set.
+//
+func (o Options) pathLength() int {
+	if !o.MeasurePath {
+		return o.PathLength
+	}
+	for skip := 1; ; skip++ {
+		_, file, line, ok := runtime.Caller(skip)
+		if !ok {
+			return o.PathLength
+		}
+		if strings.HasSuffix(file, "_test.go") {
+			// 'go test' indents by 4 and adds "{file}:{line}: ":
+			return 4 + len(filepath.Base(file)) + len(strconv.Itoa(line)) + 3
+		}
+	}
+}
+
+// IsDigits() is the same as Is() except that 'digits' is used in place
+// of both the Digits32 and Digits64 settings (for just this one call).
+// That is, 'float32', 'float64', '[]float32', and '[]float64' values are
+// compared (and shown) using at most 'digits' significant digits:
+//
+//      u.IsDigits(3, 0.333, 1.0/3, "a third")  // Passes.
+//
+// The Options used are not modified.
+//
+func IsDigits(
+	digits int, want, got interface{}, desc string, t TestingT,
+) bool {
+	t.Helper()
+	return Default.IsDigits(digits, want, got, desc, t)
+}
+
+// See tutl.IsDigits() for documentation.
+func (o Options) IsDigits(
+	digits int, want, got interface{}, desc string, t TestingT,
+) bool {
+	t.Helper()
+	o.Digits32 = digits
+	o.Digits64 = digits
+	return o.Is(want, got, desc, t)
 }
 
 // IsNot() tests that the first two arguments are converted to different
@@ -363,19 +884,104 @@ func IsNot(hate, got interface{}, desc string, t TestingT) bool {
 }
 
 // See tutl.IsNot() for documentation.
-func (o Options) IsNot(hate, got interface{}, desc string, t TestingT) bool {
+func (o Options) IsNot(
+	hate, got interface{}, desc string, t TestingT,
+) (passed bool) {
 	t.Helper()
-	vhate := o.V(hate)
-	vgot := o.V(got)
-	if vhate != vgot {
-		//  t.Log("hate:", vhate, " got:", vgot, " for:", desc)
+	defer o.hooks(desc)(&passed)
+	desc = o.descOf(desc)
+	if !o.same(hate, got) {
+		if o.Verbose {
+			t.Log("OK: Got " + o.ReplaceNewlines(o.S(got)) + " not " +
+				o.ReplaceNewlines(o.S(hate)) + " for " + desc + ".")
+		}
 		return true
 	}
+	short := o.shortDesc(desc)
 	t.Error(
-		"Got unwanted " + o.ReplaceNewlines(o.S(got)) + " for " + desc + ".")
+		"Got unwanted " + o.ReplaceNewlines(o.S(got)) + " for " + short + ".")
+	o.logFullDesc(short, desc, t)
+	return false
+}
+
+// That() tests that 'pred(got)' returns 'true'.  If not, then a diagnostic
+// is displayed which also causes the unit test to fail.  The diagnostic is
+// similar to "Got {got} which fails predicate for {desc}." where S() is
+// used for 'got'.  This is useful for one-off checks that are not simple
+// equality:
+//
+//      u.That(n, "n is even", func(v interface{}) bool {
+//          return 0 == v.(int)%2
+//      })
+//
+// See also tutl.ThatT() which avoids the need for a type assertion.
+//
+// That() returns whether the test passed.
+//
+func That(
+	got interface{}, desc string, t TestingT, pred func(interface{}) bool,
+) bool {
+	t.Helper()
+	return Default.That(got, desc, t, pred)
+}
+
+// See tutl.That() for documentation.
+func (o Options) That(
+	got interface{}, desc string, t TestingT, pred func(interface{}) bool,
+) (passed bool) {
+	t.Helper()
+	defer o.hooks(desc)(&passed)
+	desc = o.descOf(desc)
+	if pred(got) {
+		return true
+	}
+	t.Error("Got " + o.ReplaceNewlines(o.S(got)) +
+		" which fails predicate for " + desc + ".")
 	return false
 }
 
+// IsAll() runs each of the 'checks' against 'got' (passing 'desc' and 't'
+// along) so that several assertions about one value can be written as a
+// single unit.  Assertions that take just 'got', 'desc', and 't' [such as
+// tutl.NotZero(), tutl.IsUTF8(), or tutl.NotEmpty()] can be passed
+// directly.  Assertions that take other arguments are adapted with a
+// small closure:
+//
+//      tutl.IsAll(resp, "response", t,
+//          tutl.NotZero,
+//          func(got interface{}, desc string, t tutl.TestingT) bool {
+//              return tutl.HasType("*api.Response", got, desc, t)
+//          },
+//      )
+//
+// Every check is run (even after one fails) and each reports its own
+// failures.
+//
+// IsAll() returns the number of checks that failed.
+//
+func IsAll(
+	got interface{}, desc string, t TestingT,
+	checks ...func(got interface{}, desc string, t TestingT) bool,
+) int {
+	t.Helper()
+	return Default.IsAll(got, desc, t, checks...)
+}
+
+// See tutl.IsAll() for documentation.
+func (o Options) IsAll(
+	got interface{}, desc string, t TestingT,
+	checks ...func(got interface{}, desc string, t TestingT) bool,
+) (failures int) {
+	t.Helper()
+	defer o.hooksN(desc)(&failures)
+	for _, check := range checks {
+		if !check(got, desc, t) {
+			failures++
+		}
+	}
+	return failures
+}
+
 // HasType() tests that the type of the 2nd argument ('got') is equal to the
 // first argument ('want', a string).  That is, it checks that
 // 'want == fmt.Sprintf("%T", got)'.  If not, then a diagnostic is displayed
@@ -404,13 +1010,166 @@ func HasType(want string, got interface{}, desc string, t TestingT) bool {
 // See tutl.HasType() for documentation.
 func (o Options) HasType(
 	want string, got interface{}, desc string, t TestingT,
+) (passed bool) {
+	t.Helper()
+	defer o.hooks(desc)(&passed)
+	tgot := typeName(got)
+	return o.noHooks().Is(want, tgot, desc, t)
+}
+
+// typeName() returns the type of 'v' as shown by "%T", except that an
+// untyped 'nil' gives "nil".
+//
+func typeName(v interface{}) string {
+	if nil == v {
+		return "nil"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// PanicsWithType() calls 'run' and tests that it panics with a value of
+// the type named by 'wantType' [compared to fmt.Sprintf("%T", value) just
+// like HasType() does].  This suits code that panics with structured
+// values rather than strings:
+//
+//      v := tutl.PanicsWithType("*app.ConfigError", "bad port", t, func() {
+//          MustLoad("port: -1")
+//      })
+//
+// If 'run' does not panic, then a diagnostic similar to "No panic for
+// {desc}." is displayed.  If it panics with a value of another type, then
+// a diagnostic similar to "Got {type} not {wantType} for {desc}." is
+// displayed.  Either also causes the unit test to fail.
+//
+// PanicsWithType() returns the value passed to panic() (or 'nil' if there
+// was no panic) so that it can be checked further.
+//
+func PanicsWithType(
+	wantType string, desc string, t TestingT, run func(),
+) interface{} {
+	t.Helper()
+	return Default.PanicsWithType(wantType, desc, t, run)
+}
+
+// See tutl.PanicsWithType() for documentation.
+func (o Options) PanicsWithType(
+	wantType string, desc string, t TestingT, run func(),
+) interface{} {
+	t.Helper()
+	passed := false
+	defer o.hooks(desc)(&passed)
+	failure := GetPanic(run)
+	if nil == failure {
+		t.Errorf("No panic for %s.", o.descOf(desc))
+		return nil
+	}
+	passed = o.noHooks().HasType(wantType, failure, desc, t)
+	return failure
+}
+
+// PanicsThat() calls 'run' and tests that it panics with a value for which
+// 'pred' returns 'true'.  This suits structured panic values where only
+// part of the value matters:
+//
+//      u.PanicsThat("bad port", t, func() { MustLoad("port: -1") },
+//          func(v interface{}) bool {
+//              ce, ok := v.(*app.ConfigError)
+//              return ok && "port" == ce.Field
+//          })
+//
+// If 'run' does not panic, then a diagnostic similar to "No panic for
+// {desc}." is displayed.  If 'pred' returns 'false', then a diagnostic
+// similar to "Got panic {value} not matching predicate for {desc}." is
+// displayed, where S() is used for the value.  Either also causes the unit
+// test to fail.  A panic in 'pred' is also reported as a failure.
+//
+// PanicsThat() returns whether the test passed.
+//
+func PanicsThat(
+	desc string, t TestingT, run func(), pred func(interface{}) bool,
 ) bool {
 	t.Helper()
-	tgot := "nil"
-	if nil != got {
-		tgot = fmt.Sprintf("%T", got)
+	return Default.PanicsThat(desc, t, run, pred)
+}
+
+// See tutl.PanicsThat() for documentation.
+func (o Options) PanicsThat(
+	desc string, t TestingT, run func(), pred func(interface{}) bool,
+) (passed bool) {
+	t.Helper()
+	defer o.hooks(desc)(&passed)
+	desc = o.descOf(desc)
+	failure := GetPanic(run)
+	if nil == failure {
+		t.Errorf("No panic for %s.", desc)
+		return false
+	}
+	if p := GetPanic(func() { passed = pred(failure) }); nil != p {
+		t.Errorf("Panic in predicate for %s: %v", desc, p)
+		return false
+	} else if !passed {
+		t.Error("Got panic " + o.ReplaceNewlines(o.S(failure)) +
+			" not matching predicate for " + desc + ".")
+	}
+	return passed
+}
+
+// IsZero() tests that 'got' is the zero value for its type [as determined
+// by reflect.Value.IsZero()].  An untyped 'nil' is also considered zero.
+// This is handy for checking that fields got reset:
+//
+//      u.IsZero(cache.lastHit, "lastHit after Reset()", t)
+//
+// If 'got' is not zero, then a diagnostic similar to "Got non-zero {got}
+// for {desc}." is displayed (which also causes the unit test to fail),
+// where S() is used for 'got'.
+//
+// IsZero() returns whether the test passed.
+//
+func IsZero(got interface{}, desc string, t TestingT) bool {
+	t.Helper()
+	return Default.IsZero(got, desc, t)
+}
+
+// See tutl.IsZero() for documentation.
+func (o Options) IsZero(
+	got interface{}, desc string, t TestingT,
+) (passed bool) {
+	t.Helper()
+	defer o.hooks(desc)(&passed)
+	desc = o.descOf(desc)
+	if nil == got || reflect.ValueOf(got).IsZero() {
+		return true
+	}
+	t.Error(
+		"Got non-zero " + o.ReplaceNewlines(o.S(got)) + " for " + desc + ".")
+	return false
+}
+
+// NotZero() is the opposite of IsZero().  It tests that 'got' is not the
+// zero value for its type (and is not an untyped 'nil').  On failure, the
+// diagnostic is similar to "Got zero {type} for {desc}.".
+//
+// NotZero() returns whether the test passed.
+//
+func NotZero(got interface{}, desc string, t TestingT) bool {
+	t.Helper()
+	return Default.NotZero(got, desc, t)
+}
+
+// See tutl.NotZero() for documentation.
+func (o Options) NotZero(
+	got interface{}, desc string, t TestingT,
+) (passed bool) {
+	t.Helper()
+	defer o.hooks(desc)(&passed)
+	desc = o.descOf(desc)
+	if nil != got && !reflect.ValueOf(got).IsZero() {
+		return true
 	}
-	return o.Is(want, tgot, desc, t)
+	tgot := typeName(got)
+	t.Error("Got zero " + tgot + " for " + desc + ".")
+	return false
 }
 
 // Circa() tests that the 2nd and 3rd arguments are approximately equal to
@@ -424,6 +1183,24 @@ func (o Options) HasType(
 // if they are the same to 'digits' significant digits.  Passing 'digits' as
 // less than 1 or more than 15 is not useful.
 //
+// Note that this rounds each value (to the nearest, with ties going to the
+// even digit, based on the exact binary value) before comparing.  So two
+// values that differ by much less than one unit in the last digit can be
+// considered different if they straddle a rounding boundary while values
+// that differ by nearly a whole unit can be considered equal.  With 4
+// 'digits':
+//
+//      1.2345 vs 1.2346  ->  1.234 vs 1.235  (not equal)
+//      1.2349 vs 1.2351  ->  1.235 vs 1.235  (equal)
+//
+// If Options.CircaTruncates is set, then each value is instead truncated
+// to 'digits' significant digits (after converting it to the shortest
+// decimal representation that exactly identifies it, as from
+// strconv.FormatFloat(v, 'e', -1, 64)).  With 4 'digits':
+//
+//      1.2345 vs 1.2346  ->  1.234 vs 1.234  (equal)
+//      1.2349 vs 1.2351  ->  1.234 vs 1.235  (not equal)
+//
 // Circa() returns whether the test passed, which is useful for skipping
 // tests that would make no sense to run given a prior failure or to display
 // extra debug information only when a test fails.
@@ -436,17 +1213,121 @@ func Circa(digits int, want, got float64, desc string, t TestingT) bool {
 // See tutl.Circa() for documentation.
 func (o Options) Circa(
 	digits int, want, got float64, desc string, t TestingT,
-) bool {
+) (passed bool) {
 	t.Helper()
-	swant := fmt.Sprintf("%.*g", digits, want)
-	sgot := fmt.Sprintf("%.*g", digits, got)
+	defer o.hooks(desc)(&passed)
+	desc = o.descOf(desc)
+	swant := o.circa(digits, want)
+	sgot := o.circa(digits, got)
 	if swant == sgot {
+		if o.Verbose {
+			t.Log("OK: Got " + sgot + " for " + desc + ".")
+		}
 		return true
 	}
-	t.Error("Got " + sgot + " not " + swant + " for " + desc + ".")
+	short := o.shortDesc(desc)
+	t.Error("Got " + sgot + " not " + swant + " for " + short + ".")
+	o.logFullDesc(short, desc, t)
 	return false
 }
 
+// circa() formats 'v' to 'digits' significant digits for Circa().
+func (o Options) circa(digits int, v float64) string {
+	if o.CircaTruncates {
+		v = truncDigits(digits, v)
+	}
+	return fmt.Sprintf("%.*g", digits, v)
+}
+
+// truncDigits() returns 'v' truncated (toward zero) to 'digits' significant
+// decimal digits.
+//
+func truncDigits(digits int, v float64) float64 {
+	if digits < 1 {
+		digits = 1
+	}
+	e := strconv.FormatFloat(v, 'e', -1, 64)
+	exp := strings.IndexByte(e, 'e')
+	if exp < 0 { // NaN or Inf
+		return v
+	}
+	mant := e[:exp]
+	keep := digits + 1 // The leading digit and '.'
+	if strings.HasPrefix(mant, "-") {
+		keep++
+	}
+	if keep < len(mant) {
+		mant = mant[:keep]
+	}
+	t, err := strconv.ParseFloat(mant+e[exp:], 64)
+	if nil != err {
+		return v
+	}
+	return t
+}
+
+// NearULP() tests that 'got' is within 'maxULPs' units-in-the-last-place
+// of 'want'.  That is, that there are no more than 'maxULPs'-1 'float64'
+// values that lie between them.  If not, then a diagnostic is displayed
+// which also causes the unit test to fail.  The diagnostic is similar to
+// "Got {got} not {want} (off by {n} ULPs) for {desc}." where both values
+// are shown with all of their digits.
+//
+// Positive and negative zero are considered equal.  An infinity is only
+// near an identical infinity.  A NaN is only near another NaN.
+//
+// NearULP() returns whether the test passed.
+//
+func NearULP(want, got float64, maxULPs int, desc string, t TestingT) bool {
+	t.Helper()
+	return Default.NearULP(want, got, maxULPs, desc, t)
+}
+
+// See tutl.NearULP() for documentation.
+func (o Options) NearULP(
+	want, got float64, maxULPs int, desc string, t TestingT,
+) (passed bool) {
+	t.Helper()
+	defer o.hooks(desc)(&passed)
+	desc = o.descOf(desc)
+	swant := strconv.FormatFloat(want, 'g', -1, 64)
+	sgot := strconv.FormatFloat(got, 'g', -1, 64)
+	dist := ""
+	switch {
+	case math.IsNaN(want) || math.IsNaN(got):
+		if math.IsNaN(want) && math.IsNaN(got) {
+			return true
+		}
+	case math.IsInf(want, 0) || math.IsInf(got, 0):
+		if want == got {
+			return true
+		}
+	default:
+		a, b := ulpOrder(want), ulpOrder(got)
+		if a < b {
+			a, b = b, a
+		}
+		d := uint64(a) - uint64(b)
+		if 0 <= maxULPs && d <= uint64(maxULPs) {
+			return true
+		}
+		dist = fmt.Sprintf(" (off by %d ULPs)", d)
+	}
+	t.Error("Got " + sgot + " not " + swant + dist + " for " + desc + ".")
+	return false
+}
+
+// ulpOrder() maps 'float64' values onto 'int64's such that adjacent
+// (finite) floating-point values map to adjacent integers.
+//
+func ulpOrder(f float64) int64 {
+	i := int64(math.Float64bits(f))
+	if i < 0 {
+		return math.MinInt64 - i
+	}
+	return i
+}
+
 // Like() is most often used to test error messages (or other complex
 // strings).  It lets you perform multiple tests against a single value.
 // Each test checks that the value converts into a string that either
@@ -463,6 +1344,11 @@ func (o Options) Circa(
 // only pass if the string does not match.  To specify a regular expression
 // that starts with a "!" character, simply escape it as `\!` or "[!]".
 //
+// If Options.TrimLikeInput is set, then leading and trailing white space
+// (including newlines) is removed from the value's string representation
+// before any of the matches are checked.  This applies to every match
+// string in the call, including negated ones and regular expressions.
+//
 // Like() returns the number of matches that failed.
 //
 // If 'got' is 'nil', the empty string, or becomes the empty string, then
@@ -478,14 +1364,29 @@ func Like(got interface{}, desc string, t TestingT, match ...string) int {
 // See tutl.Like() for documentation.
 func (o Options) Like(
 	got interface{}, desc string, t TestingT, match ...string,
+) (failures int) {
+	t.Helper()
+	defer o.hooksN(desc)(&failures)
+	return o.like(got, desc, t, match, nil)
+}
+
+// like() implements Like() and LikeLabeled().  If 'labels' is not 'nil',
+// then 'labels[i]' is the label for 'match[i]'.
+//
+func (o Options) like(
+	got interface{}, desc string, t TestingT, match, labels []string,
 ) int {
 	t.Helper()
+	desc = o.descOf(desc)
 	if 0 == len(match) {
 		t.Errorf("Called Like() with too few arguments in test code.")
 		return 1
 	}
 
 	sgot := o.V(got)
+	if o.TrimLikeInput {
+		sgot = strings.TrimSpace(sgot)
+	}
 	empty := ""
 	if nil == got {
 		empty = "nil"
@@ -503,8 +1404,14 @@ func (o Options) Like(
 	invalid := 0
 	lgot := strings.ToLower(sgot)
 	and := ""
-	for _, m := range match {
+	lim := o.limitFailures(t)
+	for i, m := range match {
+		lbl := ""
+		if nil != labels {
+			lbl = " (label: '" + labels[i] + "')"
+		}
 		if "" == m || "!" == m {
+			lim.done()
 			t.Error(`Match strings passed to Like() must not be empty nor "!"`)
 			return len(match)
 		}
@@ -519,28 +1426,391 @@ func (o Options) Like(
 				failed++
 				sMatch := o.ReplaceNewlines(m[1:])
 				if negate {
-					t.Errorf(and+"Found unwanted <%s>...", sMatch)
+					lim.Errorf(and+"Found unwanted <%s>%s...", sMatch, lbl)
 				} else {
-					t.Errorf(and+"No <%s>...", sMatch)
+					lim.Errorf(and+"No <%s>%s...", sMatch, lbl)
 				}
 			}
 		} else if re, err := regexp.Compile(m); nil != err {
 			invalid++
-			t.Errorf(and+"Invalid regexp (%s) in test code: %v", m, err)
+			lim.Errorf(and+"Invalid regexp (%s) in test code: %v", m, err)
 		} else if negate == ("" != re.FindString(sgot)) {
 			failed++
 			if negate {
-				t.Errorf(and+"Like unwanted /%s/...", m)
+				lim.Errorf(and+"Like unwanted /%s/%s...", m, lbl)
 			} else {
-				t.Errorf(and+"Not like /%s/...", m)
+				lim.Errorf(and+"Not like /%s/%s...", m, lbl)
 			}
 		}
 		if 0 < failed {
 			and = "and "
 		}
 	}
+	lim.done()
 	if 0 < failed {
 		t.Errorf("In <%s> for %s.", sgot, desc)
 	}
 	return failed + invalid
 }
+
+// LikeLabeled() is the same as Like() except that each match string is
+// given a label that describes what it checks for.  The keys of 'labeled'
+// are the labels and the values are the match strings.  The labels are
+// included in the diagnostics, making it easier to tell which concern
+// failed when there are many match strings:
+//
+//      u.LikeLabeled(err, "load error", t, map[string]string{
+//          "error prefix": "^load:",
+//          "names file":   "*config.yaml",
+//      })
+//
+// might report "No <config.yaml> (label: 'names file')...".  The matches
+// are checked in the order of their labels (sorted as strings).
+//
+// LikeLabeled() returns the number of matches that failed.
+//
+func LikeLabeled(
+	got interface{}, desc string, t TestingT, labeled map[string]string,
+) int {
+	t.Helper()
+	return Default.LikeLabeled(got, desc, t, labeled)
+}
+
+// See tutl.LikeLabeled() for documentation.
+func (o Options) LikeLabeled(
+	got interface{}, desc string, t TestingT, labeled map[string]string,
+) (failures int) {
+	t.Helper()
+	defer o.hooksN(desc)(&failures)
+	labels := make([]string, 0, len(labeled))
+	for l := range labeled {
+		labels = append(labels, l)
+	}
+	sort.Strings(labels)
+	match := make([]string, len(labels))
+	for i, l := range labels {
+		match[i] = labeled[l]
+	}
+	return o.like(got, desc, t, match, labels)
+}
+
+// IsUTF8() tests that V(got) is a valid UTF-8 string.  If it is not, then
+// a diagnostic is displayed which also causes the unit test to fail.  The
+// diagnostic is similar to "Got invalid UTF-8 (\xFF) at byte 3 of {got}
+// for {desc}." where S() is used for 'got'.
+//
+// IsUTF8() returns whether the test passed.
+//
+func IsUTF8(got interface{}, desc string, t TestingT) bool {
+	t.Helper()
+	return Default.IsUTF8(got, desc, t)
+}
+
+// See tutl.IsUTF8() for documentation.
+func (o Options) IsUTF8(
+	got interface{}, desc string, t TestingT,
+) (passed bool) {
+	t.Helper()
+	defer o.hooks(desc)(&passed)
+	desc = o.descOf(desc)
+	s := o.V(got)
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if utf8.RuneError == r && size <= 1 {
+			t.Errorf("Got invalid UTF-8 (\\x%02X) at byte %d of %s for %s.",
+				s[i], i, o.ReplaceNewlines(o.S(got)), desc)
+			return false
+		}
+		i += size
+	}
+	return true
+}
+
+// RegexpMatches() tests a regular expression (rather than a string).  It
+// checks that 'pattern' compiles and then that it matches each string in
+// 'shouldMatch' and does not match any string in 'shouldNotMatch'.  Each
+// failure is reported via a diagnostic similar to "/{pattern}/ did not
+// match {example} for {desc}." or "/{pattern}/ matched unwanted {example}
+// for {desc}." (which also cause the unit test to fail).
+//
+// RegexpMatches() returns the number of failures (1 if 'pattern' is not
+// a valid regular expression).
+//
+func RegexpMatches(
+	pattern string, desc string, t TestingT,
+	shouldMatch []string, shouldNotMatch []string,
+) int {
+	t.Helper()
+	return Default.RegexpMatches(
+		pattern, desc, t, shouldMatch, shouldNotMatch)
+}
+
+// See tutl.RegexpMatches() for documentation.
+func (o Options) RegexpMatches(
+	pattern string, desc string, t TestingT,
+	shouldMatch []string, shouldNotMatch []string,
+) (failures int) {
+	t.Helper()
+	defer o.hooksN(desc)(&failures)
+	desc = o.descOf(desc)
+	re, err := regexp.Compile(pattern)
+	if nil != err {
+		t.Errorf("Invalid regexp (%s) for %s: %v", pattern, desc, err)
+		return 1
+	}
+	lim := o.limitFailures(t)
+	defer lim.done()
+	for _, s := range shouldMatch {
+		if !re.MatchString(s) {
+			failures++
+			lim.Errorf("/%s/ did not match %s for %s.",
+				pattern, o.ReplaceNewlines(o.S(s)), desc)
+		}
+	}
+	for _, s := range shouldNotMatch {
+		if re.MatchString(s) {
+			failures++
+			lim.Errorf("/%s/ matched unwanted %s for %s.",
+				pattern, o.ReplaceNewlines(o.S(s)), desc)
+		}
+	}
+	return failures
+}
+
+// ErrorFormat() tests that the message of the error 'got' follows the Go
+// conventions for error strings.  The rules checked are:
+//
+//      The message must not be empty.
+//      The first character must not be an uppercase letter, unless the
+//          second character is also uppercase (as in "EOF ..." or "HTTP
+//          ..."); see Options.StrictErrorCase to disallow this exception.
+//      The last character must not be punctuation (one of ".:;,!?") nor
+//          whitespace (including a newline).
+//
+// Each violation is reported via a diagnostic similar to "Error {msg}
+// starts with uppercase for {desc}." (which also causes the unit test to
+// fail).  A 'nil' 'got' is also reported as a failure.
+//
+// ErrorFormat() returns whether the test passed.
+//
+func ErrorFormat(got error, desc string, t TestingT) bool {
+	t.Helper()
+	return Default.ErrorFormat(got, desc, t)
+}
+
+// See tutl.ErrorFormat() for documentation.
+func (o Options) ErrorFormat(
+	got error, desc string, t TestingT,
+) (passed bool) {
+	t.Helper()
+	defer o.hooks(desc)(&passed)
+	desc = o.descOf(desc)
+	if nil == got {
+		t.Error("Got nil error for " + desc + ".")
+		return false
+	}
+	msg := got.Error()
+	if "" == msg {
+		t.Error("Got empty error message for " + desc + ".")
+		return false
+	}
+	sMsg := o.ReplaceNewlines(o.S(got))
+	passed = true
+	first, size := utf8.DecodeRuneInString(msg)
+	second, _ := utf8.DecodeRuneInString(msg[size:])
+	if unicode.IsUpper(first) &&
+		(o.StrictErrorCase || !unicode.IsUpper(second)) {
+		passed = false
+		t.Error("Error " + sMsg + " starts with uppercase for " + desc + ".")
+	}
+	last, _ := utf8.DecodeLastRuneInString(msg)
+	if strings.ContainsRune(".:;,!?", last) || unicode.IsSpace(last) {
+		passed = false
+		t.Error("Error " + sMsg + " ends with " + Rune(last) +
+			" for " + desc + ".")
+	}
+	return passed
+}
+
+// ErrorChain() tests that each of the 'wants' errors appears in the chain
+// of errors that 'got' wraps [as checked by errors.Is()].  This verifies
+// the whole wrapping structure of an error rather than just one sentinel:
+//
+//      u.ErrorChain(err, "save", t, ErrNotFound, fs.ErrNotExist)
+//
+// Each of the 'wants' that is not found is reported via a diagnostic
+// similar to "Missing {want} in error chain for {desc}." (which also
+// causes the unit test to fail) and then each error in the chain of 'got'
+// is logged [following both 'Unwrap() error' and 'Unwrap() []error'].
+// A 'nil' 'got' is reported just once.
+//
+// ErrorChain() returns the number of 'wants' that were not found.
+//
+func ErrorChain(got error, desc string, t TestingT, wants ...error) int {
+	t.Helper()
+	return Default.ErrorChain(got, desc, t, wants...)
+}
+
+// See tutl.ErrorChain() for documentation.
+func (o Options) ErrorChain(
+	got error, desc string, t TestingT, wants ...error,
+) (failures int) {
+	t.Helper()
+	defer o.hooksN(desc)(&failures)
+	desc = o.descOf(desc)
+	if nil == got && 0 < len(wants) {
+		t.Errorf("Got nil error not a chain for %s.", desc)
+		return len(wants)
+	}
+	lim := o.limitFailures(t)
+	defer lim.done()
+	for _, want := range wants {
+		if !errors.Is(got, want) {
+			failures++
+			lim.Errorf("Missing %s in error chain for %s.",
+				o.ReplaceNewlines(o.S(want)), desc)
+		}
+	}
+	if 0 < failures {
+		for _, err := range unwrapAll(got) {
+			lim.Logf("    chain: %s (%T)", o.ReplaceNewlines(o.S(err)), err)
+		}
+	}
+	return failures
+}
+
+// unwrapAll() returns 'err' and every error that it wraps, in depth-first
+// order.
+//
+func unwrapAll(err error) []error {
+	if nil == err {
+		return nil
+	}
+	all := []error{err}
+	switch e := err.(type) {
+	case interface{ Unwrap() error }:
+		all = append(all, unwrapAll(e.Unwrap())...)
+	case interface{ Unwrap() []error }:
+		for _, sub := range e.Unwrap() {
+			all = append(all, unwrapAll(sub)...)
+		}
+	}
+	return all
+}
+
+// StringerIs() calls got.String() and tests that the result equals 'want'
+// just like Is() does.  Because 'got' must be a fmt.Stringer, this also
+// checks (when the test compiles) that the type has a String() method:
+//
+//      u.StringerIs("StatusActive", StatusActive, "status name", t)
+//
+// If 'got' is 'nil' (or a 'nil' pointer whose String() method panics),
+// then that is reported as a failure rather than causing the test to
+// panic.
+//
+// StringerIs() returns whether the test passed.
+//
+func StringerIs(want string, got fmt.Stringer, desc string, t TestingT) bool {
+	t.Helper()
+	return Default.StringerIs(want, got, desc, t)
+}
+
+// See tutl.StringerIs() for documentation.
+func (o Options) StringerIs(
+	want string, got fmt.Stringer, desc string, t TestingT,
+) (passed bool) {
+	t.Helper()
+	defer o.hooks(desc)(&passed)
+	if nil == got {
+		t.Errorf("Got nil Stringer for %s.", o.descOf(desc))
+		return false
+	}
+	var s string
+	if p := GetPanic(func() { s = got.String() }); nil != p {
+		t.Errorf("Panic calling %T.String() for %s: %v", got, o.descOf(desc), p)
+		return false
+	}
+	return o.noHooks().Is(want, s, desc, t)
+}
+
+// GoStringIs() calls got.GoString() and tests that the result equals
+// 'want' just like Is() does.  This tests how the value is shown via "%#v",
+// which types implement to control how they appear in debugging output:
+//
+//      u.GoStringIs(`Point{X: 1, Y: 2}`, Point{1, 2}, "Point %#v", t)
+//
+// 'nil' values are handled just like for StringerIs().
+//
+// GoStringIs() returns whether the test passed.
+//
+func GoStringIs(
+	want string, got fmt.GoStringer, desc string, t TestingT,
+) bool {
+	t.Helper()
+	return Default.GoStringIs(want, got, desc, t)
+}
+
+// See tutl.GoStringIs() for documentation.
+func (o Options) GoStringIs(
+	want string, got fmt.GoStringer, desc string, t TestingT,
+) (passed bool) {
+	t.Helper()
+	defer o.hooks(desc)(&passed)
+	if nil == got {
+		t.Errorf("Got nil GoStringer for %s.", o.descOf(desc))
+		return false
+	}
+	var s string
+	if p := GetPanic(func() { s = got.GoString() }); nil != p {
+		t.Errorf("Panic calling %T.GoString() for %s: %v",
+			got, o.descOf(desc), p)
+		return false
+	}
+	return o.noHooks().Is(want, s, desc, t)
+}
+
+// IsAfter() applies 'normalize' to both 'want' and 'got' and then tests
+// that the results are equal just like Is() does.  This tests that two
+// values are equal "up to" some normalization, such as ignoring letter
+// case, ordering, or rounding:
+//
+//      lower := func(v interface{}) interface{} {
+//          return strings.ToLower(tutl.V(v))
+//      }
+//      u.IsAfter(lower, "Hello", greeting, "greeting (any case)", t)
+//
+// The diagnostic (if any) shows the normalized values.  If ShowOriginal
+// is set (as it is by default) and either value was changed by 'normalize',
+// then a second line shows the original values, similar to:
+//
+//      Got "help" not "hello" for greet.
+//      Originals: got "Help" not "Hello".
+//
+// IsAfter() returns whether the test passed.
+//
+func IsAfter(
+	normalize func(interface{}) interface{},
+	want, got interface{}, desc string, t TestingT,
+) bool {
+	t.Helper()
+	return Default.IsAfter(normalize, want, got, desc, t)
+}
+
+// See tutl.IsAfter() for documentation.
+func (o Options) IsAfter(
+	normalize func(interface{}) interface{},
+	want, got interface{}, desc string, t TestingT,
+) (passed bool) {
+	t.Helper()
+	defer o.hooks(desc)(&passed)
+	nWant, nGot := normalize(want), normalize(got)
+	if o.noHooks().Is(nWant, nGot, desc, t) {
+		return true
+	}
+	if sWant, sGot := o.S(want), o.S(got); o.ShowOriginal &&
+		(sWant != o.S(nWant) || sGot != o.S(nGot)) {
+		t.Log("Originals: got " + o.ReplaceNewlines(sGot) +
+			" not " + o.ReplaceNewlines(sWant) + ".")
+	}
+	return false
+}
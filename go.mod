@@ -1 +1,3 @@
 module github.com/TyeMcQueen/go-tutl
+
+go 1.20
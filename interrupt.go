@@ -5,6 +5,7 @@ import (
 	"os"
 	"os/signal"
 	"runtime/debug"
+	"strings"
 	"sync"
 	"syscall"
 )
@@ -93,3 +94,18 @@ func AtInterrupt(f func()) func() {
 	atInterrupt = append(atInterrupt, f)
 	return f
 }
+
+// panicStack() returns the stack trace of the calling goroutine, which
+// must be running a function deferred during a panic.  The frames for the
+// deferred function and for the panic itself are left out so that the
+// trace starts where the panic happened.
+//
+func panicStack() string {
+	lines := strings.Split(string(debug.Stack()), "\n")
+	for i, l := range lines {
+		if strings.HasPrefix(l, "panic(") && i+2 <= len(lines) {
+			return strings.Join(append(lines[:1:1], lines[i+2:]...), "\n")
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,323 @@
+package tutl
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// IsReaderString() reads everything from 'got' and then tests that the
+// resulting string is equal to 'want' just like Is() does.  A failure to
+// read from 'got' is reported separately (and also causes the test to
+// fail).
+//
+// IsReaderString() returns whether the test passed.
+//
+func IsReaderString(want string, got io.Reader, desc string, t TestingT) bool {
+	t.Helper()
+	return Default.IsReaderString(want, got, desc, t)
+}
+
+// See tutl.IsReaderString() for documentation.
+func (o Options) IsReaderString(
+	want string, got io.Reader, desc string, t TestingT,
+) (passed bool) {
+	t.Helper()
+	defer o.hooks(desc)(&passed)
+	b, err := io.ReadAll(got)
+	if nil != err {
+		t.Errorf("Error reading %s: %v", desc, err)
+		return false
+	}
+	return o.noHooks().Is(want, string(b), desc, t)
+}
+
+// FileIs() reads the file at 'path' and tests that its contents are equal
+// to 'want' just like Is() does [that is, 'want' is converted via V(), so
+// a 'string' or '[]byte' 'want' is compared directly].  The diagnostic is
+// similar to "Got {contents} not {want} for file {path}.".
+//
+// If the file can't be read (such as because it does not exist), then a
+// diagnostic similar to "Can't read file {path}: {error}" is reported
+// instead (which also causes the unit test to fail).
+//
+// FileIs() returns whether the test passed.
+//
+func FileIs(want interface{}, path string, t TestingT) bool {
+	t.Helper()
+	return Default.FileIs(want, path, t)
+}
+
+// See tutl.FileIs() for documentation.
+func (o Options) FileIs(
+	want interface{}, path string, t TestingT,
+) (passed bool) {
+	t.Helper()
+	desc := "file " + path
+	defer o.hooks(desc)(&passed)
+	b, err := os.ReadFile(path)
+	if nil != err {
+		t.Errorf("Can't read %s: %v", o.descOf(desc), err)
+		return false
+	}
+	return o.noHooks().Is(want, string(b), desc, t)
+}
+
+// DirHas() tests that the directory 'dir' contains exactly the entries
+// listed in 'wantFiles' (in any order).  This catches both files that
+// were not generated and stray output:
+//
+//      u.DirHas(out, t, "go.mod", "main.go", "internal/")
+//
+// By default, only the top level of 'dir' is listed and each
+// subdirectory is given as its name followed by "/".  If
+// Options.RecurseDirs is set, then 'dir' is walked recursively and each
+// file (but not directory) is given as its path relative to 'dir', using
+// "/" as the separator, such as "internal/db/db.go".
+//
+// Each entry that is missing or unexpected is reported via a diagnostic
+// similar to "Missing {file} for dir {dir}." or "Got unexpected {file}
+// for dir {dir}." (which also cause the unit test to fail).  If 'dir'
+// can't be read, then that is reported instead.
+//
+// DirHas() returns the number of entries reported (or 1 if 'dir' can't be
+// read).
+//
+func DirHas(dir string, t TestingT, wantFiles ...string) int {
+	t.Helper()
+	return Default.DirHas(dir, t, wantFiles...)
+}
+
+// See tutl.DirHas() for documentation.
+func (o Options) DirHas(
+	dir string, t TestingT, wantFiles ...string,
+) (failures int) {
+	t.Helper()
+	desc := "dir " + dir
+	defer o.hooksN(desc)(&failures)
+	desc = o.descOf(desc)
+	got, err := o.listDir(dir)
+	if nil != err {
+		t.Errorf("Can't list %s: %v", desc, err)
+		return 1
+	}
+	return o.setDiff(wantFiles, got, desc, t)
+}
+
+// listDir() returns the entries of 'dir' as described for DirHas().
+func (o Options) listDir(dir string) ([]string, error) {
+	if !o.RecurseDirs {
+		ents, err := os.ReadDir(dir)
+		if nil != err {
+			return nil, err
+		}
+		names := make([]string, len(ents))
+		for i, e := range ents {
+			names[i] = e.Name()
+			if e.IsDir() {
+				names[i] += "/"
+			}
+		}
+		return names, nil
+	}
+	var names []string
+	err := filepath.WalkDir(dir, func(
+		path string, e fs.DirEntry, err error,
+	) error {
+		if nil != err || e.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if nil == err {
+			names = append(names, filepath.ToSlash(rel))
+		}
+		return err
+	})
+	return names, err
+}
+
+// ScanLike() reads 'r' one line at a time and checks each line using the
+// same 'match' strings as Like() (see Like() for how "*" and "!" prefixes
+// work).  This lets you check a large log without holding all of it in
+// memory.
+//
+//      u.ScanLike(logFile, "server log", t, "*listening on", "!*panic")
+//
+// A positive match string passes as soon as any line matches it.  A
+// negated match string fails on the first line that matches it, which is
+// reported via a diagnostic similar to "Found unwanted <panic> on line
+// 12: <{line}>".  After the whole of 'r' is read, each positive match
+// string that no line matched is reported via a diagnostic similar to
+// "No <listening on> in any line..." and a final "In {n} lines for
+// {desc}." line is reported.  These also cause the unit test to fail.
+//
+// Lines do not include their trailing newline (nor any "\r" before it).
+// A line can be of any length.
+//
+// ScanLike() returns the number of matches that failed (plus 1 if reading
+// from 'r' failed).
+//
+func ScanLike(r io.Reader, desc string, t TestingT, match ...string) int {
+	t.Helper()
+	return Default.ScanLike(r, desc, t, match...)
+}
+
+// See tutl.ScanLike() for documentation.
+func (o Options) ScanLike(
+	r io.Reader, desc string, t TestingT, match ...string,
+) (failures int) {
+	t.Helper()
+	defer o.hooksN(desc)(&failures)
+	desc = o.descOf(desc)
+	if 0 == len(match) {
+		t.Errorf("Called ScanLike() with too few arguments in test code.")
+		return 1
+	}
+	lim := o.limitFailures(t)
+	matchers := make([]*lineMatcher, 0, len(match))
+	for _, m := range match {
+		if "" == m || "!" == m {
+			t.Error(
+				`Match strings passed to ScanLike() must not be empty nor "!"`)
+			return len(match)
+		}
+		lm := &lineMatcher{}
+		if '!' == m[0] {
+			m = m[1:]
+			lm.negate = true
+		}
+		if '*' == m[0] {
+			lm.show = m[1:]
+			lm.sub = strings.ToLower(m[1:])
+		} else if re, err := regexp.Compile(m); nil != err {
+			failures++
+			lim.Errorf("Invalid regexp (%s) in test code: %v", m, err)
+			continue
+		} else {
+			lm.re = re
+		}
+		matchers = append(matchers, lm)
+	}
+
+	failed := 0
+	lines := 0
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadString('\n')
+		if "" != line {
+			lines++
+			line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+			for _, lm := range matchers {
+				if lm.done || !lm.matches(line) {
+					continue
+				}
+				lm.done = true
+				if lm.negate {
+					failed++
+					lim.Errorf("Found unwanted %s on line %d: <%s>",
+						lm, lines, o.ReplaceNewlines(line))
+				}
+			}
+		}
+		if io.EOF == err {
+			break
+		} else if nil != err {
+			failures++
+			lim.Errorf("Error reading %s: %v", desc, err)
+			break
+		}
+	}
+	for _, lm := range matchers {
+		if !lm.negate && !lm.done {
+			failed++
+			lim.Errorf("No %s in any line...", lm)
+		}
+	}
+	lim.done()
+	if 0 < failed {
+		t.Errorf("In %d lines for %s.", lines, desc)
+	}
+	return failures + failed
+}
+
+// lineMatcher holds one parsed match string for ScanLike().  'done' is set
+// once a line has matched it.
+//
+type lineMatcher struct {
+	negate bool
+	show   string
+	sub    string
+	re     *regexp.Regexp
+	done   bool
+}
+
+// matches() returns whether 'line' matches (ignoring any negation).
+func (lm *lineMatcher) matches(line string) bool {
+	if nil != lm.re {
+		return "" != lm.re.FindString(line)
+	}
+	return strings.Contains(strings.ToLower(line), lm.sub)
+}
+
+// String() shows the match as "<sub-string>" or "/regexp/".
+func (lm *lineMatcher) String() string {
+	if nil != lm.re {
+		return "/" + lm.re.String() + "/"
+	}
+	return "<" + lm.show + ">"
+}
+
+// CaptureWriter is an io.Writer that records everything written to it so
+// that a test can then check what was written:
+//
+//      w := tutl.NewCaptureWriter()
+//      Report(w, results)
+//      u.Like(w.WroteString(), "report", "^Passed: 3$")
+//
+// It is safe for multiple goroutines to write to a CaptureWriter at once
+// (each call to Write() is recorded as a unit) and to read what was
+// written while writes are still happening.
+//
+type CaptureWriter struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+// NewCaptureWriter() returns a new, empty CaptureWriter.
+func NewCaptureWriter() *CaptureWriter {
+	return &CaptureWriter{}
+}
+
+// Write() records 'p' and never fails.
+func (w *CaptureWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
+
+// Wrote() returns a copy of all of the bytes written so far.
+func (w *CaptureWriter) Wrote() []byte {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]byte(nil), w.buf.Bytes()...)
+}
+
+// WroteString() returns all of the bytes written so far as a 'string'.
+func (w *CaptureWriter) WroteString() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.String()
+}
+
+// Reset() discards everything written so far.
+func (w *CaptureWriter) Reset() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.buf.Reset()
+}
@@ -0,0 +1,854 @@
+package tutl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FieldType() tests that the JSON value found at 'key' inside of 'got' has
+// the type 'want', which must be one of "string", "number", "bool",
+// "object", "array", or "null".  The value itself is not checked.
+//
+// 'got' can be a 'string' or '[]byte' holding JSON or any value that can
+// be converted to JSON via json.Marshal().  'key' is a list of map keys
+// and/or array indices separated by "." characters, such as "items.0.id".
+// A "*" matches every key or index [see Element()].
+//
+// If the key is not found or the value has a different type, then a
+// diagnostic is displayed which also causes the unit test to fail.  The
+// diagnostic is similar to "Got {type} not {want} at {key} for {desc}.".
+//
+// FieldType() returns whether the test passed.
+//
+func FieldType(
+	want string, got interface{}, key string, desc string, t TestingT,
+) bool {
+	t.Helper()
+	return Default.FieldType(want, got, key, desc, t)
+}
+
+// See tutl.FieldType() for documentation.
+func (o Options) FieldType(
+	want string, got interface{}, key string, desc string, t TestingT,
+) (passed bool) {
+	t.Helper()
+	defer o.hooks(desc)(&passed)
+	desc = o.descOf(desc)
+	doc, err := fromJson(got)
+	if nil != err {
+		t.Errorf("Invalid JSON for %s: %v", desc, err)
+		return false
+	}
+	val, ok := element(doc, key)
+	if !ok {
+		t.Errorf("No %s found for %s.", key, desc)
+		return false
+	}
+	tgot := jsonType(val)
+	if want == tgot {
+		return true
+	}
+	t.Errorf("Got %s not %s at %s for %s.", tgot, want, key, desc)
+	return false
+}
+
+// JsonFields() converts 'got' to JSON via json.Marshal() and tests that
+// the resulting JSON object has exactly the top-level keys listed in
+// 'want' (in any order).  This can guard the names used when a type is
+// serialized against accidental changes to field names or 'json' tags.
+//
+//      u.JsonFields([]string{"id", "name", "created_at"}, User{}, "User")
+//
+// Each key that is missing or unexpected is reported via a diagnostic
+// similar to "Missing JSON field {key} for {desc}." or "Got unexpected
+// JSON field {key} for {desc}." (which also cause the unit test to fail).
+//
+// JsonFields() returns the number of keys reported (or 1 if 'got' can't
+// be converted to a JSON object).
+//
+func JsonFields(want []string, got interface{}, desc string, t TestingT) int {
+	t.Helper()
+	return Default.JsonFields(want, got, desc, t)
+}
+
+// See tutl.JsonFields() for documentation.
+func (o Options) JsonFields(
+	want []string, got interface{}, desc string, t TestingT,
+) (failures int) {
+	t.Helper()
+	defer o.hooksN(desc)(&failures)
+	desc = o.descOf(desc)
+	j, err := json.Marshal(got)
+	if nil != err {
+		t.Errorf("Can't convert %T to JSON for %s: %v", got, desc, err)
+		return 1
+	}
+	keys, err := jsonKeys(j)
+	if nil != err {
+		t.Errorf("Got %s not a JSON object for %s.", j, desc)
+		return 1
+	}
+	lim := o.limitFailures(t)
+	defer lim.done()
+	have := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		have[k] = true
+	}
+	failed := 0
+	for _, k := range want {
+		if !have[k] {
+			failed++
+			lim.Error("Missing JSON field " + o.S(k) + " for " + desc + ".")
+		}
+		delete(have, k)
+	}
+	for _, k := range keys {
+		if have[k] {
+			failed++
+			lim.Error(
+				"Got unexpected JSON field " + o.S(k) + " for " + desc + ".")
+		}
+	}
+	return failed
+}
+
+// JsonArrayUnordered() tests that 'want' and 'got' are JSON arrays that
+// hold the same elements, ignoring their order.  Each of 'want' and 'got'
+// can be a 'string' or '[]byte' holding JSON or any value that can be
+// converted to JSON via json.Marshal().
+//
+//      u.JsonArrayUnordered(`[{"id":1},{"id":2}]`, resp.Items, "items", t)
+//
+// Each element is converted to canonical JSON (so object key order and
+// white space do not matter) and the arrays are compared as multisets (so
+// the number of duplicates matters).  Each difference is reported via a
+// diagnostic similar to "Missing {elem} for {desc}." or "Got unexpected
+// {elem} for {desc}." (which also cause the unit test to fail).
+//
+// JsonArrayUnordered() returns whether the test passed.
+//
+func JsonArrayUnordered(want, got interface{}, desc string, t TestingT) bool {
+	t.Helper()
+	return Default.JsonArrayUnordered(want, got, desc, t)
+}
+
+// See tutl.JsonArrayUnordered() for documentation.
+func (o Options) JsonArrayUnordered(
+	want, got interface{}, desc string, t TestingT,
+) (passed bool) {
+	t.Helper()
+	defer o.hooks(desc)(&passed)
+	desc = o.descOf(desc)
+	wElems, ok := o.jsonArray(want, "want", desc, t)
+	if !ok {
+		return false
+	}
+	gElems, ok := o.jsonArray(got, "got", desc, t)
+	if !ok {
+		return false
+	}
+	lim := o.limitFailures(t)
+	defer lim.done()
+	counts := make(map[string]int, len(wElems))
+	for _, e := range wElems {
+		counts[e]++
+	}
+	var extra []string
+	for _, e := range gElems {
+		if 0 < counts[e] {
+			counts[e]--
+		} else {
+			extra = append(extra, e)
+		}
+	}
+	passed = true
+	for _, e := range wElems {
+		if 0 < counts[e] {
+			counts[e]--
+			passed = false
+			lim.Error("Missing " + e + " for " + desc + ".")
+		}
+	}
+	for _, e := range extra {
+		passed = false
+		lim.Error("Got unexpected " + e + " for " + desc + ".")
+	}
+	return passed
+}
+
+// jsonArray() returns the canonical JSON for each element of the JSON
+// array 'v' (see fromJson()).  If 'v' is not a JSON array, then that is
+// reported to 't' (mentioning 'which' value) and 'false' is returned.
+//
+func (o Options) jsonArray(
+	v interface{}, which, desc string, t TestingT,
+) ([]string, bool) {
+	t.Helper()
+	doc, err := fromJson(v)
+	if nil != err {
+		t.Errorf("Invalid JSON for '%s' for %s: %v", which, desc, err)
+		return nil, false
+	}
+	arr, ok := doc.([]interface{})
+	if !ok {
+		t.Errorf("Got JSON %s not array for '%s' for %s.",
+			jsonType(doc), which, desc)
+		return nil, false
+	}
+	elems := make([]string, len(arr))
+	for i, e := range arr {
+		elems[i] = snapshot(e)
+	}
+	return elems, true
+}
+
+// IsJsonNear() tests that 'want' and 'got' hold the same JSON except that
+// each pair of numbers only needs to be within 'tolerance' of each other.
+// Each of 'want' and 'got' can be a 'string' or '[]byte' holding JSON or
+// any value that can be converted to JSON via json.Marshal().
+//
+//      u.IsJsonNear(`{"lat": 37.7749, "lng": -122.4194}`, loc, 1e-4,
+//          "location", t)
+//
+// The two documents are walked in parallel.  Strings, booleans, and nulls
+// must match exactly, objects must have the same keys, and arrays must have
+// the same length.  Each difference is reported via a diagnostic that
+// includes the path to the value (such as "points.2.x") and which also
+// causes the unit test to fail, such as "Got 1.5 not 1.2 (+/-0.1) at
+// points.2.x for {desc}." or "No points.3 found for {desc}.".
+//
+// IsJsonNear() returns the number of differences reported (or 1 if either
+// value is not valid JSON).
+//
+func IsJsonNear(
+	want, got interface{}, tolerance float64, desc string, t TestingT,
+) int {
+	t.Helper()
+	return Default.IsJsonNear(want, got, tolerance, desc, t)
+}
+
+// See tutl.IsJsonNear() for documentation.
+func (o Options) IsJsonNear(
+	want, got interface{}, tolerance float64, desc string, t TestingT,
+) (failures int) {
+	t.Helper()
+	defer o.hooksN(desc)(&failures)
+	desc = o.descOf(desc)
+	wDoc, err := fromJson(want)
+	if nil != err {
+		t.Errorf("Invalid JSON for 'want' for %s: %v", desc, err)
+		return 1
+	}
+	gDoc, err := fromJson(got)
+	if nil != err {
+		t.Errorf("Invalid JSON for 'got' for %s: %v", desc, err)
+		return 1
+	}
+	lim := o.limitFailures(t)
+	defer lim.done()
+	return jsonNear("", wDoc, gDoc, tolerance, desc, lim)
+}
+
+// jsonNear() reports each difference between 'want' and 'got' (as returned
+// by fromJson()) found at or below 'path'.
+//
+func jsonNear(
+	path string, want, got interface{}, tol float64, desc string, t TestingT,
+) int {
+	t.Helper()
+	if wType, gType := jsonType(want), jsonType(got); wType != gType {
+		t.Errorf("Got %s not %s at %s for %s.", gType, wType, orTop(path), desc)
+		return 1
+	}
+	switch w := want.(type) {
+	case float64:
+		g := got.(float64)
+		if math.Abs(w-g) <= tol {
+			return 0
+		}
+		within := ""
+		if 0 != tol {
+			within = " (+/-" + num(tol) + ")"
+		}
+		t.Errorf("Got %s not %s%s at %s for %s.", num(g), num(w), within,
+			orTop(path), desc)
+		return 1
+	case map[string]interface{}:
+		g := got.(map[string]interface{})
+		keys := make([]string, 0, len(w)+len(g))
+		for k := range w {
+			keys = append(keys, k)
+		}
+		for k := range g {
+			if _, ok := w[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		failures := 0
+		for _, k := range keys {
+			sub := jsonPath(path, k)
+			wv, inW := w[k]
+			gv, inG := g[k]
+			switch {
+			case !inG:
+				failures++
+				t.Errorf("No %s found for %s.", sub, desc)
+			case !inW:
+				failures++
+				t.Errorf("Got unexpected %s for %s.", sub, desc)
+			default:
+				failures += jsonNear(sub, wv, gv, tol, desc, t)
+			}
+		}
+		return failures
+	case []interface{}:
+		g := got.([]interface{})
+		failures := 0
+		for i := 0; i < len(w) || i < len(g); i++ {
+			sub := jsonPath(path, strconv.Itoa(i))
+			switch {
+			case len(g) <= i:
+				failures++
+				t.Errorf("No %s found for %s.", sub, desc)
+			case len(w) <= i:
+				failures++
+				t.Errorf("Got unexpected %s for %s.", sub, desc)
+			default:
+				failures += jsonNear(sub, w[i], g[i], tol, desc, t)
+			}
+		}
+		return failures
+	}
+	if sWant, sGot := snapshot(want), snapshot(got); sWant != sGot {
+		t.Errorf("Got %s not %s at %s for %s.", sGot, sWant, orTop(path), desc)
+		return 1
+	}
+	return 0
+}
+
+// jsonPath() returns the path to 'key' inside of the value at 'path'.
+func jsonPath(path, key string) string {
+	if "" == path {
+		return key
+	}
+	return path + "." + key
+}
+
+// num() formats 'f' using the fewest digits that exactly represent it.
+func num(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// HasInt() tests integer values found inside of a JSON document without
+// the loss of precision that comes from decoding JSON numbers as 'float64'
+// values (which can't exactly represent integers beyond 2**53).  'got' can
+// be a 'string' or '[]byte' holding JSON or any value that can be
+// converted to JSON via json.Marshal().
+//
+// 'pairs' holds pairs of arguments.  Each pair is a 'string' key (using
+// the "."-separated syntax of FieldType()) followed by the wanted integer
+// value (of any integer type):
+//
+//      u.HasInt(body, "order", t, "id", int64(9007199254740993), "qty", 2)
+//
+// The JSON number found at each key is parsed as an integer directly from
+// its text and compared exactly.  Each failure is reported via a
+// diagnostic similar to "Got {num} not {want} at {key} for {desc}.".  A
+// JSON number that is not an integer (such as 1.5 or 1e3) is reported as
+// a failure that notes that precision would be lost.
+//
+// HasInt() returns the number of pairs that failed (or 1 if 'got' is not
+// valid JSON).
+//
+func HasInt(
+	got interface{}, desc string, t TestingT, pairs ...interface{},
+) int {
+	t.Helper()
+	return Default.HasInt(got, desc, t, pairs...)
+}
+
+// See tutl.HasInt() for documentation.
+func (o Options) HasInt(
+	got interface{}, desc string, t TestingT, pairs ...interface{},
+) (failures int) {
+	t.Helper()
+	defer o.hooksN(desc)(&failures)
+	desc = o.descOf(desc)
+	if 0 != len(pairs)%2 {
+		t.Errorf("HasInt() needs key/value pairs in test code for %s.", desc)
+		return 1
+	}
+	doc, err := decodeJson(got, true)
+	if nil != err {
+		t.Errorf("Invalid JSON for %s: %v", desc, err)
+		return 1
+	}
+	lim := o.limitFailures(t)
+	defer lim.done()
+	for i := 0; i < len(pairs); i += 2 {
+		key, ok := pairs[i].(string)
+		if !ok {
+			failures++
+			lim.Errorf("HasInt() needs a string key not %T in test code"+
+				" for %s.", pairs[i], desc)
+			continue
+		}
+		want := pairs[i+1]
+		val, ok := element(doc, key)
+		if !ok {
+			failures++
+			lim.Errorf("No %s found for %s.", key, desc)
+			continue
+		}
+		num, ok := val.(json.Number)
+		if !ok {
+			failures++
+			lim.Errorf("Got %s not number at %s for %s.",
+				jsonType(val), key, desc)
+			continue
+		}
+		same, err := sameInt(want, string(num))
+		if nil != err {
+			failures++
+			lim.Errorf("Got %s at %s for %s: %v", num, key, desc, err)
+		} else if !same {
+			failures++
+			lim.Errorf("Got %s not %s at %s for %s.", num, o.V(want), key, desc)
+		}
+	}
+	return failures
+}
+
+// sameInt() returns whether the integer 'want' equals the JSON number
+// 'num'.  It returns an error if 'want' is not an integer or 'num' is not
+// an integer of the same signedness.
+//
+func sameInt(want interface{}, num string) (bool, error) {
+	wv := reflect.ValueOf(want)
+	switch wv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64:
+		n, err := strconv.ParseInt(num, 10, 64)
+		if nil != err {
+			return false, intErr(num, err)
+		}
+		return n == wv.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(num, 10, 64)
+		if nil != err {
+			return false, intErr(num, err)
+		}
+		return n == wv.Uint(), nil
+	}
+	return false, fmt.Errorf("want %T not an integer in test code", want)
+}
+
+// intErr() describes why the JSON number 'num' could not be parsed.
+func intErr(num string, err error) error {
+	if strings.ContainsAny(num, ".eE") {
+		return fmt.Errorf("not an integer (precision would be lost)")
+	}
+	return err
+}
+
+// AgreeOn() tests that 'a' and 'b' hold equal values at each of the
+// 'keys', ignoring any other differences.  This is useful for comparing
+// two versions of a record on just the fields that should not change:
+//
+//      u.AgreeOn(before, after, []string{"id", "owner.name", "tags.0"},
+//          "updated user", t)
+//
+// Each of 'a' and 'b' can be a 'string' or '[]byte' holding JSON or any
+// value that can be converted to JSON via json.Marshal().  Each key holds
+// "."-separated object keys and array indices (as for HasInt()).  The
+// values found are compared as canonical JSON (so object key order and
+// white space do not matter).
+//
+// Each disagreement is reported via a diagnostic similar to "Got {b value}
+// not {a value} at {key} for {desc}." and each key that is missing from
+// either document is reported via a diagnostic similar to "No {key} found
+// in 'b' for {desc}.".  These also cause the unit test to fail.
+//
+// AgreeOn() returns the number of keys reported (or 1 if either value is
+// not valid JSON).
+//
+func AgreeOn(a, b interface{}, keys []string, desc string, t TestingT) int {
+	t.Helper()
+	return Default.AgreeOn(a, b, keys, desc, t)
+}
+
+// See tutl.AgreeOn() for documentation.
+func (o Options) AgreeOn(
+	a, b interface{}, keys []string, desc string, t TestingT,
+) (failures int) {
+	t.Helper()
+	defer o.hooksN(desc)(&failures)
+	desc = o.descOf(desc)
+	aDoc, err := fromJson(a)
+	if nil != err {
+		t.Errorf("Invalid JSON for 'a' for %s: %v", desc, err)
+		return 1
+	}
+	bDoc, err := fromJson(b)
+	if nil != err {
+		t.Errorf("Invalid JSON for 'b' for %s: %v", desc, err)
+		return 1
+	}
+	lim := o.limitFailures(t)
+	defer lim.done()
+	for _, key := range keys {
+		aVal, inA := element(aDoc, key)
+		bVal, inB := element(bDoc, key)
+		switch {
+		case !inA && !inB:
+			failures++
+			lim.Errorf("No %s found in 'a' nor 'b' for %s.", key, desc)
+		case !inA:
+			failures++
+			lim.Errorf("No %s found in 'a' for %s.", key, desc)
+		case !inB:
+			failures++
+			lim.Errorf("No %s found in 'b' for %s.", key, desc)
+		default:
+			if sA, sB := snapshot(aVal), snapshot(bVal); sA != sB {
+				failures++
+				lim.Errorf("Got %s not %s at %s for %s.", sB, sA, key, desc)
+			}
+		}
+	}
+	return failures
+}
+
+// JsonDeterministic() converts 'value' to JSON (via json.Marshal()) 10
+// times and tests that every result is identical.  This catches custom
+// MarshalJSON() methods that leak the random iteration order of maps,
+// which otherwise causes flaky golden-file and API tests.
+//
+// If the encodings differ, then a diagnostic similar to "Got {json} from
+// encoding 4 not {first} for {desc}." is displayed (which also causes the
+// unit test to fail).  Only the first difference is reported.
+//
+// JsonDeterministic() returns whether the test passed.
+//
+func JsonDeterministic(value interface{}, desc string, t TestingT) bool {
+	t.Helper()
+	return Default.JsonDeterministic(value, desc, t)
+}
+
+// See tutl.JsonDeterministic() for documentation.
+func (o Options) JsonDeterministic(
+	value interface{}, desc string, t TestingT,
+) (passed bool) {
+	t.Helper()
+	defer o.hooks(desc)(&passed)
+	desc = o.descOf(desc)
+	var first []byte
+	for i := 1; i <= 10; i++ {
+		j, err := json.Marshal(value)
+		if nil != err {
+			t.Errorf("Can't marshal %s to JSON: %v", desc, err)
+			return false
+		}
+		if 1 == i {
+			first = j
+		} else if !bytes.Equal(first, j) {
+			o.gotNot(string(j)+fmt.Sprintf(" from encoding %d", i),
+				string(first), desc, t)
+			return false
+		}
+	}
+	return true
+}
+
+// Unchanged() checks that calling 'run' does not modify 'value'.  This is
+// useful for testing that a function does not modify its arguments:
+//
+//      u.Unchanged("Sort copies", &input, func() { _ = sorted(input) })
+//
+// A snapshot of 'value' is taken by converting it to JSON before 'run' is
+// called.  If the JSON for 'value' is different after 'run' returns, then
+// a diagnostic is displayed which also causes the unit test to fail.  The
+// diagnostic is similar to "Got {after} not {before} for {desc} (changed
+// by run)." where the before and after values are shown as JSON.
+//
+// If 'value' can't be converted to JSON (such as if it contains a channel
+// or a function), then a deep copy of 'value' is made via reflection [just
+// like DeepCopy() does] and compared to 'value' after 'run' returns via
+// StructDiff(), which reports each changed field (and logs its notes) only
+// if something changed.  'value' should usually be a pointer, slice, or
+// map, otherwise 'run' could not modify it anyway.
+//
+// Changes to unexported struct fields are never detected, since neither
+// JSON nor StructDiff() looks at them.  Nor are changes to data reached
+// only via a channel or function.
+//
+// Unchanged() returns whether the test passed.
+//
+func Unchanged(desc string, t TestingT, value interface{}, run func()) bool {
+	t.Helper()
+	return Default.Unchanged(desc, t, value, run)
+}
+
+// See tutl.Unchanged() for documentation.
+func (o Options) Unchanged(
+	desc string, t TestingT, value interface{}, run func(),
+) (passed bool) {
+	t.Helper()
+	defer o.hooks(desc)(&passed)
+	if _, err := json.Marshal(value); nil != err {
+		before := copier{}.copy(reflect.ValueOf(&value).Elem()).Interface()
+		run()
+		desc += " (changed by run)"
+		if 0 == o.noHooks().StructDiff(before, value, desc, &ErrorCollector{}) {
+			return true
+		}
+		o.noHooks().StructDiff(before, value, desc, t)
+		return false
+	}
+	desc = o.descOf(desc)
+	before := snapshot(value)
+	run()
+	after := snapshot(value)
+	if before == after {
+		return true
+	}
+	o.gotNot(after, before, desc+" (changed by run)", t)
+	return false
+}
+
+// MarshalsTo() converts 'got' to JSON via json.Marshal() and tests that
+// the result is equivalent to the JSON in 'want'.  Both documents are
+// compared in a canonical form so differences in the order of object keys
+// or in white space do not matter:
+//
+//      u.MarshalsTo(`{"id": 7, "name": "x"}`, Item{Name: "x", ID: 7}, "x", t)
+//
+// On a mismatch, a diagnostic similar to "Got {json} not {want} for
+// {desc}." is reported (which also causes the unit test to fail), where
+// both documents are shown in canonical form.  If both are JSON objects,
+// then the differences are also logged as a table via MapDiff().
+//
+// MarshalsTo() returns whether the test passed.
+//
+func MarshalsTo(want string, got interface{}, desc string, t TestingT) bool {
+	t.Helper()
+	return Default.MarshalsTo(want, got, desc, t)
+}
+
+// See tutl.MarshalsTo() for documentation.
+func (o Options) MarshalsTo(
+	want string, got interface{}, desc string, t TestingT,
+) (passed bool) {
+	t.Helper()
+	defer o.hooks(desc)(&passed)
+	desc = o.descOf(desc)
+	wDoc, err := fromJson(want)
+	if nil != err {
+		t.Errorf("Invalid 'want' JSON for %s: %v", desc, err)
+		return false
+	}
+	j, err := json.Marshal(got)
+	if nil != err {
+		t.Errorf("Can't marshal %s to JSON: %v", desc, err)
+		return false
+	}
+	gDoc, err := fromJson(j)
+	if nil != err {
+		t.Errorf("Invalid JSON produced for %s: %v", desc, err)
+		return false
+	}
+	sWant, sGot := snapshot(wDoc), snapshot(gDoc)
+	if sWant == sGot {
+		return true
+	}
+	o.gotNot(sGot, sWant, desc, t)
+	wMap, wIsMap := wDoc.(map[string]interface{})
+	gMap, gIsMap := gDoc.(map[string]interface{})
+	if wIsMap && gIsMap {
+		t.Log("\n" + o.MapDiff(wMap, gMap))
+	}
+	return false
+}
+
+// snapshot() returns the JSON for 'v' or, if that fails, 'v' formatted
+// via "%+v".
+//
+func snapshot(v interface{}) string {
+	if j, err := json.Marshal(v); nil == err {
+		return string(j)
+	}
+	return fmt.Sprintf("%+v", v)
+}
+
+// fromJson() returns the generic form (maps, slices, float64s, etc.) of a
+// JSON document.  'v' can be a 'string' or '[]byte' holding JSON or any
+// value that json.Marshal() can convert to JSON.
+//
+func fromJson(v interface{}) (interface{}, error) {
+	return decodeJson(v, false)
+}
+
+// decodeJson() is fromJson() except that, if 'useNumber' is set, then
+// JSON numbers are returned as 'json.Number' values (strings) rather than
+// as 'float64's so no precision is lost.
+//
+func decodeJson(v interface{}, useNumber bool) (interface{}, error) {
+	var j []byte
+	switch d := v.(type) {
+	case string:
+		j = []byte(d)
+	case []byte:
+		j = d
+	default:
+		var err error
+		if j, err = json.Marshal(v); nil != err {
+			return nil, err
+		}
+	}
+	dec := json.NewDecoder(bytes.NewReader(j))
+	if useNumber {
+		dec.UseNumber()
+	}
+	var doc interface{}
+	if err := dec.Decode(&doc); nil != err {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("extra data after JSON value")
+	}
+	return doc, nil
+}
+
+// Element() returns the value found inside of a JSON document at 'key'
+// so that further checks can be made on it.  'got' can be a 'string' or
+// '[]byte' holding JSON or any value that can be converted to JSON via
+// json.Marshal().  'key' uses the "."-separated syntax of FieldType().
+// Values are returned in their generic JSON forms (numbers as 'float64',
+// objects as 'map[string]interface{}', arrays as '[]interface{}', etc.).
+//
+// A "*" in 'key' matches every value in an object (in the order of their
+// keys) or every element of an array, and the result is then a
+// '[]interface{}' holding each of them.  Any parts of 'key' after the "*"
+// are applied to each of those values, so this checks the "enabled" value
+// of each entry in "config":
+//
+//      on := tutl.Element(body, "config.*.enabled", t)
+//      u.Is("[true true true]", on, "all enabled")
+//
+// If any of those values lacks the rest of 'key', then nothing is found.
+// Each further "*" adds another level of nesting to the result.
+//
+// If 'got' is not valid JSON or nothing is found at 'key', then a
+// diagnostic is displayed (which also causes the unit test to fail) and
+// 'nil' is returned.
+//
+func Element(got interface{}, key string, t TestingT) interface{} {
+	t.Helper()
+	doc, err := fromJson(got)
+	if nil != err {
+		t.Errorf("Invalid JSON: %v", err)
+		return nil
+	}
+	val, ok := element(doc, key)
+	if !ok {
+		t.Errorf("No %s found.", key)
+		return nil
+	}
+	return val
+}
+
+// element() returns the value found by following the "."-separated map
+// keys and array indices in 'key' starting from 'doc' (as returned by
+// fromJson()).  An empty 'key' returns 'doc' itself.  See Element() for
+// how "*" is handled.
+//
+func element(doc interface{}, key string) (interface{}, bool) {
+	if "" == key {
+		return doc, true
+	}
+	return elementAt(doc, strings.Split(key, "."))
+}
+
+// elementAt() is element() with 'key' already split into 'parts'.
+func elementAt(doc interface{}, parts []string) (interface{}, bool) {
+	for n, k := range parts {
+		if "*" == k {
+			return eachElement(doc, parts[n+1:])
+		}
+		switch d := doc.(type) {
+		case map[string]interface{}:
+			v, ok := d[k]
+			if !ok {
+				return nil, false
+			}
+			doc = v
+		case []interface{}:
+			i, err := strconv.Atoi(k)
+			if nil != err || i < 0 || len(d) <= i {
+				return nil, false
+			}
+			doc = d[i]
+		default:
+			return nil, false
+		}
+	}
+	return doc, true
+}
+
+// eachElement() applies the rest of a key, 'parts', to each value in the
+// object or array 'doc' and returns the results.
+//
+func eachElement(doc interface{}, parts []string) (interface{}, bool) {
+	var vals []interface{}
+	switch d := doc.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(d))
+		for k := range d {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			vals = append(vals, d[k])
+		}
+	case []interface{}:
+		vals = d
+	default:
+		return nil, false
+	}
+	found := make([]interface{}, len(vals))
+	for i, v := range vals {
+		e, ok := elementAt(v, parts)
+		if !ok {
+			return nil, false
+		}
+		found[i] = e
+	}
+	return found, true
+}
+
+// jsonType() returns the JSON type name of a value returned by fromJson().
+func jsonType(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case float64, json.Number:
+		return "number"
+	case bool:
+		return "bool"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	}
+	return fmt.Sprintf("%T", v)
+}
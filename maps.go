@@ -0,0 +1,391 @@
+package tutl
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Map is a generic map as produced by decoding a JSON object.  A nested
+// value can be either a Map or a 'map[string]interface{}'.
+//
+type Map map[string]interface{}
+
+// M() returns a Map built from alternating keys and values, which can be
+// less noisy than a Map literal when building expected structures in
+// tests.  Calls to M() can be nested to build nested objects:
+//
+//      u.Matches(tutl.M("id", "number", "owner", tutl.M("admin", false)),
+//          body, "user", t)
+//
+// M() is meant to be used in test code so it panics if given an odd number
+// of arguments or if any key is not a 'string'.
+//
+func M(pairs ...interface{}) Map {
+	if 1 == len(pairs)%2 {
+		panic(fmt.Sprintf("tutl.M() needs key/value pairs, got %d arguments",
+			len(pairs)))
+	}
+	m := make(Map, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		k, ok := pairs[i].(string)
+		if !ok {
+			panic(fmt.Sprintf("tutl.M() needs string keys, got %T (%v)"+
+				" as argument %d", pairs[i], pairs[i], i+1))
+		}
+		m[k] = pairs[i+1]
+	}
+	return m
+}
+
+// MapDiff() returns a table, one line per key, showing each key where the
+// two maps differ.  Each line lists the key, the value from 'want', and
+// the value from 'got' in aligned columns, preceded by a heading line.
+// A key missing from one of the maps shows "(missing)" in that column.
+//
+// Nested maps are compared key by key and their keys are shown with
+// "."-separated prefixes, such as "db.port".  Values are compared via V()
+// and shown via S().  If the maps do not differ, then "" is returned.
+//
+// For example:
+//
+//      key      want       got
+//      db.port  5432       5433
+//      debug    (missing)  true
+//
+func MapDiff(want, got Map) string {
+	return Default.MapDiff(want, got)
+}
+
+// See tutl.MapDiff() for documentation.
+func (o Options) MapDiff(want, got Map) string {
+	rows := o.mapDiffRows("", want, got, nil)
+	if 0 == len(rows) {
+		return ""
+	}
+	rows = append([][3]string{{"key", "want", "got"}}, rows...)
+	var wide [2]int
+	for _, row := range rows {
+		for c := range wide {
+			if n := utf8.RuneCountInString(row[c]); wide[c] < n {
+				wide[c] = n
+			}
+		}
+	}
+	var b strings.Builder
+	for _, row := range rows {
+		for c := range wide {
+			b.WriteString(row[c])
+			pad := 2 + wide[c] - utf8.RuneCountInString(row[c])
+			b.WriteString(strings.Repeat(" ", pad))
+		}
+		b.WriteString(row[2])
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// missingCell is shown in MapDiff() for a key that is not present.
+const missingCell = "(missing)"
+
+// mapDiffRows() appends to 'rows' a {key, want, got} row for each
+// difference between 'want' and 'got' (and returns the updated slice).
+//
+func (o Options) mapDiffRows(
+	prefix string, want, got map[string]interface{}, rows [][3]string,
+) [][3]string {
+	keys := make([]string, 0, len(want)+len(got))
+	for k := range want {
+		keys = append(keys, k)
+	}
+	for k := range got {
+		if _, ok := want[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		w, inWant := want[k]
+		g, inGot := got[k]
+		wMap, wIsMap := asMap(w)
+		gMap, gIsMap := asMap(g)
+		switch {
+		case wIsMap && gIsMap:
+			rows = o.mapDiffRows(prefix+k+".", wMap, gMap, rows)
+		case !inWant:
+			rows = append(rows, [3]string{prefix + k, missingCell, o.cell(g)})
+		case !inGot:
+			rows = append(rows, [3]string{prefix + k, o.cell(w), missingCell})
+		case o.V(w) != o.V(g):
+			rows = append(rows, [3]string{prefix + k, o.cell(w), o.cell(g)})
+		}
+	}
+	return rows
+}
+
+// asMap() returns 'v' as a 'map[string]interface{}' if it is a Map or
+// already is that type.
+//
+func asMap(v interface{}) (map[string]interface{}, bool) {
+	switch m := v.(type) {
+	case Map:
+		return m, true
+	case map[string]interface{}:
+		return m, true
+	}
+	return nil, false
+}
+
+// cell() formats a value for display in one column of a table.
+func (o Options) cell(v interface{}) string {
+	return o.ReplaceNewlines(o.S(v))
+}
+
+// Matches() tests that 'got' has the shape described by 'spec'.  'got'
+// can be a 'string' or '[]byte' holding JSON or any value that can be
+// converted to JSON via json.Marshal().  It must be a JSON object that has
+// each key in 'spec' (other keys are ignored).  What each value in 'spec'
+// requires of the value at that key depends on the value in 'spec':
+//
+//      "string", "number", "bool", "object", "array", or "null":
+//          The value must be of that JSON type.
+//      "any":
+//          Any value (even 'null') is fine as long as the key is present.
+//      Any other 'string':
+//          A regular expression that the value (which must be a JSON
+//          string) must match.
+//      A Map (or 'map[string]interface{}'):
+//          The value must be a JSON object that recursively Matches() it.
+//      Anything else:
+//          The value must equal this (converted to JSON).
+//
+// For example:
+//
+//      u.Matches(tutl.Map{
+//          "id": "number",
+//          "email": `^[^@]+@[^@]+$`,
+//          "owner": tutl.Map{"name": "string", "admin": false},
+//      }, body, "user", t)
+//
+// Each violation is reported via a diagnostic that includes the path to
+// the value (such as "owner.name") and which also causes the unit test to
+// fail, such as "Got number not string at owner.name for {desc}.".
+//
+// Matches() returns the number of violations reported (or 1 if 'got' is
+// not valid JSON).
+//
+func Matches(spec Map, got interface{}, desc string, t TestingT) int {
+	t.Helper()
+	return Default.Matches(spec, got, desc, t)
+}
+
+// See tutl.Matches() for documentation.
+func (o Options) Matches(
+	spec Map, got interface{}, desc string, t TestingT,
+) (failures int) {
+	t.Helper()
+	defer o.hooksN(desc)(&failures)
+	desc = o.descOf(desc)
+	doc, err := fromJson(got)
+	if nil != err {
+		t.Errorf("Invalid JSON for %s: %v", desc, err)
+		return 1
+	}
+	lim := o.limitFailures(t)
+	defer lim.done()
+	return o.matches("", spec, doc, desc, lim)
+}
+
+// jsonTypeNames holds the type names that Matches() accepts in a spec.
+var jsonTypeNames = map[string]bool{"string": true, "number": true,
+	"bool": true, "object": true, "array": true, "null": true}
+
+// matches() reports each way that 'doc' does not match 'spec'.
+func (o Options) matches(
+	prefix string, spec map[string]interface{}, doc interface{},
+	desc string, t TestingT,
+) int {
+	t.Helper()
+	obj, ok := doc.(map[string]interface{})
+	if !ok {
+		t.Errorf("Got %s not object at %s for %s.",
+			jsonType(doc), orTop(strings.TrimSuffix(prefix, ".")), desc)
+		return 1
+	}
+	keys := make([]string, 0, len(spec))
+	for k := range spec {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	failures := 0
+	for _, k := range keys {
+		path := prefix + k
+		val, ok := obj[k]
+		if !ok {
+			failures++
+			t.Errorf("No %s found for %s.", path, desc)
+			continue
+		}
+		want := spec[k]
+		if sub, isMap := asMap(want); isMap {
+			failures += o.matches(path+".", sub, val, desc, t)
+			continue
+		}
+		s, isStr := want.(string)
+		switch {
+		case isStr && "any" == s:
+		case isStr && jsonTypeNames[s]:
+			if got := jsonType(val); s != got {
+				failures++
+				t.Errorf("Got %s not %s at %s for %s.", got, s, path, desc)
+			}
+		case isStr:
+			str, ok := val.(string)
+			re, err := regexp.Compile(s)
+			if nil != err {
+				failures++
+				t.Errorf("Invalid regexp (%s) in test code at %s for %s: %v",
+					s, path, desc, err)
+			} else if !ok {
+				failures++
+				t.Errorf("Got %s not string at %s for %s.",
+					jsonType(val), path, desc)
+			} else if !re.MatchString(str) {
+				failures++
+				t.Errorf("Got %s not like /%s/ at %s for %s.",
+					o.ReplaceNewlines(o.S(str)), s, path, desc)
+			}
+		default:
+			wDoc, err := fromJson(want)
+			if nil != err {
+				failures++
+				t.Errorf("Can't convert %T to JSON in test code at %s"+
+					" for %s: %v", want, path, desc, err)
+			} else if sWant, sGot := snapshot(wDoc), snapshot(val); sWant != sGot {
+				failures++
+				t.Errorf("Got %s not %s at %s for %s.", sGot, sWant, path, desc)
+			}
+		}
+	}
+	return failures
+}
+
+// HasMap() tests the values found inside of a JSON document at each key
+// of 'want'.  'got' can be a 'string' or '[]byte' holding JSON or any
+// value that can be converted to JSON via json.Marshal().  Each key in
+// 'want' uses the "."-separated syntax of FieldType() and the value found
+// there must equal the value in 'want' (after converting it to JSON):
+//
+//      u.HasMap(t, "order", body, tutl.Map{
+//          "id":          7,
+//          "status":      "paid",
+//          "items.0.sku": "A-1",
+//      })
+//
+// This is like passing key/value pairs to HasInt() except that the values
+// can be of any type and a Map can't have an odd number of arguments or
+// have its keys and values swapped.
+//
+// A value in 'want' that is itself a Map (or 'map[string]interface{}') is
+// not compared as a whole.  Instead, each of its keys is checked in turn,
+// recursively, so these two calls check the same things:
+//
+//      u.HasMap(t, "user", body, tutl.M("owner", tutl.M("name", "x")))
+//      u.HasMap(t, "user", body, tutl.M("owner.name", "x"))
+//
+// So, like Matches(), keys in 'got' that are not in 'want' are ignored at
+// every level.  But HasMap() compares each leaf value for equality while
+// Matches() also allows types and regular expressions to be given.
+//
+// Each failure is reported via a diagnostic similar to "Got {json} not
+// {want} at {key} for {desc}." or "No {key} found for {desc}." (which also
+// causes the unit test to fail), where {key} is the full "."-separated
+// key.  The keys are checked in sorted order.
+//
+// HasMap() returns the number of keys that failed (or 1 if 'got' is not
+// valid JSON).
+//
+func HasMap(t TestingT, desc string, got interface{}, want Map) int {
+	t.Helper()
+	return Default.HasMap(t, desc, got, want)
+}
+
+// See tutl.HasMap() for documentation.
+func (o Options) HasMap(
+	t TestingT, desc string, got interface{}, want Map,
+) (failures int) {
+	t.Helper()
+	defer o.hooksN(desc)(&failures)
+	desc = o.descOf(desc)
+	doc, err := fromJson(got)
+	if nil != err {
+		t.Errorf("Invalid JSON for %s: %v", desc, err)
+		return 1
+	}
+	lim := o.limitFailures(t)
+	defer lim.done()
+	leaves := mapLeaves("", want, nil)
+	sort.Slice(leaves, func(i, j int) bool {
+		return leaves[i].key < leaves[j].key
+	})
+	for _, l := range leaves {
+		k := l.key
+		// Marshal first so that a 'string' is not taken to be JSON:
+		j, err := json.Marshal(l.want)
+		if nil != err {
+			failures++
+			lim.Errorf("Can't convert %T to JSON in test code at %s for %s: %v",
+				l.want, k, desc, err)
+			continue
+		}
+		wDoc, _ := fromJson(j)
+		val, ok := element(doc, k)
+		if !ok {
+			failures++
+			lim.Errorf("No %s found for %s.", k, desc)
+		} else if sWant, sGot := snapshot(wDoc), snapshot(val); sWant != sGot {
+			failures++
+			lim.Errorf("Got %s not %s at %s for %s.", sGot, sWant, k, desc)
+		}
+	}
+	return failures
+}
+
+// CountChecks() returns how many values HasMap() would check for 'want',
+// without checking anything.  Nested Maps are counted by their own values
+// [see HasMap()], so an empty nested Map adds nothing.  This can confirm
+// that a large expected structure is as complete as intended:
+//
+//      u.Is(42, tutl.CountChecks(wantOrder), "order checks", t)
+//
+func CountChecks(want Map) int {
+	return len(mapLeaves("", want, nil))
+}
+
+// mapLeaf is one value that HasMap() checks and the key it is found at.
+type mapLeaf struct {
+	key  string
+	want interface{}
+}
+
+// mapLeaves() appends to 'leaves' a mapLeaf for each value in 'want' that
+// is not a map, recursing into those that are (and returns the updated
+// slice).
+//
+func mapLeaves(
+	prefix string, want map[string]interface{}, leaves []mapLeaf,
+) []mapLeaf {
+	for k, v := range want {
+		if sub, isMap := asMap(v); isMap {
+			leaves = mapLeaves(prefix+k+".", sub, leaves)
+		} else {
+			leaves = append(leaves, mapLeaf{prefix + k, v})
+		}
+	}
+	return leaves
+}
@@ -0,0 +1,191 @@
+package tutl
+
+import (
+	"math"
+	"math/bits"
+	"reflect"
+	"strconv"
+)
+
+// ParsesAs() parses the string 'got' as a number of the same type as
+// 'want' and tests that the result exactly equals 'want'.
+// Integer types are parsed via strconv.ParseInt() and unsigned types via
+// strconv.ParseUint(), both in base 10; floating point types are parsed
+// via strconv.ParseFloat().  The bit size of the type of 'want' is used
+// so an out-of-range value is a parse error.
+//
+//      u.ParsesAs(uint8(200), Format(200), "200 as uint8", t)
+//
+// If 'got' can't be parsed, then a diagnostic similar to "Can't parse
+// {got} as {type} for {desc}: {error}" is reported (which also causes the
+// unit test to fail).  A 'want' that is not a number is reported as a
+// mistake in the test code.
+//
+// A parsed value that is not equal to 'want' is reported like Is() does,
+// except that floating point values are shown with as many digits as are
+// needed to tell them apart (ignoring Options.Digits32 and Digits64).  A
+// NaN 'want' only matches a NaN.
+//
+// ParsesAs() returns whether the test passed.
+//
+func ParsesAs(want interface{}, got string, desc string, t TestingT) bool {
+	t.Helper()
+	return Default.ParsesAs(want, got, desc, t)
+}
+
+// See tutl.ParsesAs() for documentation.
+func (o Options) ParsesAs(
+	want interface{}, got string, desc string, t TestingT,
+) (passed bool) {
+	t.Helper()
+	defer o.hooks(desc)(&passed)
+	wv := reflect.ValueOf(want)
+	var parsed interface{}
+	var err error
+	switch wv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64:
+		parsed, err = strconv.ParseInt(got, 10, wv.Type().Bits())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		parsed, err = strconv.ParseUint(got, 10, wv.Type().Bits())
+	case reflect.Float32, reflect.Float64:
+		parsed, err = strconv.ParseFloat(got, wv.Type().Bits())
+	default:
+		t.Errorf("ParsesAs() needs a number not %T in test code for %s.",
+			want, o.descOf(desc))
+		return false
+	}
+	if nil != err {
+		t.Errorf("Can't parse %s as %T for %s: %v",
+			o.ReplaceNewlines(o.S(got)), want, o.descOf(desc), err)
+		return false
+	}
+	if f, ok := parsed.(float64); ok {
+		w := wv.Float()
+		if f == w || math.IsNaN(f) && math.IsNaN(w) {
+			return true
+		}
+		bits := wv.Type().Bits()
+		o.gotNot(strconv.FormatFloat(f, 'g', -1, bits),
+			strconv.FormatFloat(w, 'g', -1, bits), o.descOf(desc), t)
+		return false
+	}
+	conv := reflect.ValueOf(parsed).Convert(wv.Type()).Interface()
+	return o.noHooks().Is(want, conv, desc, t)
+}
+
+// WithinPercent() tests that 'got' is within 'percent' percent of
+// 'baseline' (relative to the magnitude of 'baseline').  This is handy
+// for coarse performance guards:
+//
+//      u.WithinPercent(baselineOpsPerSec, measured, 10, "throughput", t)
+//
+// If it is not, then a diagnostic similar to "Got 118 not within 10% of
+// 100 for {desc} (deviated 18%, allowed 10%)." is displayed (which also
+// causes the unit test to fail).  A 'baseline' of 0 only allows a 'got'
+// of 0.  A NaN value always fails.
+//
+// WithinPercent() returns whether the test passed.
+//
+func WithinPercent(
+	baseline, got, percent float64, desc string, t TestingT,
+) bool {
+	t.Helper()
+	return Default.WithinPercent(baseline, got, percent, desc, t)
+}
+
+// See tutl.WithinPercent() for documentation.
+func (o Options) WithinPercent(
+	baseline, got, percent float64, desc string, t TestingT,
+) (passed bool) {
+	t.Helper()
+	defer o.hooks(desc)(&passed)
+	desc = o.descOf(desc)
+	dev := math.Inf(1)
+	if got == baseline {
+		dev = 0
+	} else if 0 != baseline {
+		dev = 100 * math.Abs(got-baseline) / math.Abs(baseline)
+	}
+	if dev <= percent {
+		return true
+	}
+	t.Errorf(
+		"Got %s not within %s%% of %s for %s (deviated %s%%, allowed %s%%).",
+		o.S(got), o.S(percent), o.S(baseline), desc,
+		strconv.FormatFloat(dev, 'g', 3, 64), o.S(percent))
+	return false
+}
+
+// IsMultipleOf() tests that 'got' is a multiple of 'factor' (that is, that
+// got % factor is 0).  This suits checking alignment or sizing rules:
+//
+//      u.IsMultipleOf(4096, int64(len(buf)), "buffer size", t)
+//
+// If it is not, then a diagnostic similar to "Got 5000 not a multiple of
+// 4096 for {desc} (remainder 904)." is displayed (which also causes the
+// unit test to fail).  A 'factor' of 0 is reported as an error in the test
+// code.
+//
+// IsMultipleOf() returns whether the test passed.
+//
+func IsMultipleOf(factor, got int64, desc string, t TestingT) bool {
+	t.Helper()
+	return Default.IsMultipleOf(factor, got, desc, t)
+}
+
+// See tutl.IsMultipleOf() for documentation.
+func (o Options) IsMultipleOf(
+	factor, got int64, desc string, t TestingT,
+) (passed bool) {
+	t.Helper()
+	defer o.hooks(desc)(&passed)
+	desc = o.descOf(desc)
+	if 0 == factor {
+		t.Errorf("Called IsMultipleOf() with a factor of 0 in test code"+
+			" for %s.", desc)
+		return false
+	}
+	rem := got % factor
+	if 0 == rem {
+		return true
+	}
+	t.Errorf("Got %s not a multiple of %s for %s (remainder %s).",
+		o.S(got), o.S(factor), desc, o.S(rem))
+	return false
+}
+
+// IsPowerOfTwo() tests that 'got' is a power of two (1, 2, 4, 8, ...):
+//
+//      u.IsPowerOfTwo(int64(cache.Buckets()), "bucket count", t)
+//
+// If it is not, then a diagnostic similar to "Got 100 not a power of two
+// for {desc} (between 64 and 128)." is displayed (which also causes the
+// unit test to fail).  Values less than 1 are never powers of two.
+//
+// IsPowerOfTwo() returns whether the test passed.
+//
+func IsPowerOfTwo(got int64, desc string, t TestingT) bool {
+	t.Helper()
+	return Default.IsPowerOfTwo(got, desc, t)
+}
+
+// See tutl.IsPowerOfTwo() for documentation.
+func (o Options) IsPowerOfTwo(
+	got int64, desc string, t TestingT,
+) (passed bool) {
+	t.Helper()
+	defer o.hooks(desc)(&passed)
+	desc = o.descOf(desc)
+	if 0 < got && 0 == got&(got-1) {
+		return true
+	}
+	near := ""
+	if 0 < got && got < 1<<62 {
+		below := int64(1) << (bits.Len64(uint64(got)) - 1)
+		near = " (between " + o.S(below) + " and " + o.S(2*below) + ")"
+	}
+	t.Errorf("Got %s not a power of two for %s%s.", o.S(got), desc, near)
+	return false
+}
@@ -0,0 +1,8 @@
+module github.com/TyeMcQueen/go-tutl/protoeq
+
+go 1.20
+
+require (
+	github.com/TyeMcQueen/go-tutl v0.1.0
+	google.golang.org/protobuf v1.34.2
+)
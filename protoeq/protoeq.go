@@ -0,0 +1,57 @@
+/*
+
+Package protoeq lets you compare protocol buffer messages in your tests.
+It is a separate module so that only those who use it need to depend on
+the protobuf packages.
+
+	import (
+		"testing"
+
+		"github.com/TyeMcQueen/go-tutl/protoeq"
+	)
+
+	func TestReply(t *testing.T) {
+		protoeq.ProtoEqual(wantReply, client.Get(req), "Get reply", t)
+	}
+
+*/
+package protoeq
+
+import (
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/TyeMcQueen/go-tutl"
+)
+
+// ProtoEqual() tests that two messages are equal according to
+// proto.Equal().  If they are not, then a diagnostic is displayed which
+// also causes the unit test to fail.
+//
+// The diagnostic is the same as from tutl.Is() except that each message
+// is shown in the (single-line) protobuf text format.
+//
+// ProtoEqual() returns whether the test passed.
+//
+func ProtoEqual(want, got proto.Message, desc string, t tutl.TestingT) bool {
+	t.Helper()
+	if proto.Equal(want, got) {
+		return true
+	}
+	sWant := text(want)
+	sGot := text(got)
+	if sWant == sGot {
+		// Such as when the messages are of different types:
+		t.Error("Got unequal message " + sGot + " for " + desc + ".")
+		return false
+	}
+	return tutl.Is(sWant, sGot, desc, t)
+}
+
+// text() returns the protobuf text format of a message or "nil".
+func text(m proto.Message) string {
+	if nil == m {
+		return "nil"
+	}
+	return prototext.MarshalOptions{}.Format(m)
+}
@@ -0,0 +1,94 @@
+package protoeq_test
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	u "github.com/TyeMcQueen/go-tutl"
+	"github.com/TyeMcQueen/go-tutl/protoeq"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+type mock struct {
+	output []string
+}
+
+func (m *mock) Failed() bool { return false }
+func (m *mock) Helper()      {}
+
+func (m *mock) Error(args ...interface{}) { m.Log(args...) }
+
+func (m *mock) Errorf(format string, args ...interface{}) {
+	m.Logf(format, args...)
+}
+
+func (m *mock) Log(args ...interface{}) {
+	m.output = append(m.output, fmt.Sprint(args...))
+}
+
+func (m *mock) Logf(format string, args ...interface{}) {
+	m.output = append(m.output, fmt.Sprintf(format, args...))
+}
+
+func (m *mock) isOutput(desc string, t *testing.T, want ...string) {
+	t.Helper()
+	if u.Is(len(want), len(m.output), desc+" count", t) {
+		for i, o := range want {
+			u.Is(o, m.output[i], u.S(desc, " ", i), t)
+		}
+	} else {
+		t.Log("Surprise output:\n", strings.Join(m.output, "\n"))
+	}
+	m.output = nil
+}
+
+// text() returns the text form of 'm' (prototext deliberately varies its
+// spacing between builds, so expected output can't be hard-coded).
+func text(m proto.Message) string {
+	if nil == m {
+		return "nil"
+	}
+	return prototext.MarshalOptions{}.Format(m)
+}
+
+// isFailure() returns the failure that tutl.Is() reports for the text
+// forms of 'want' and 'got'.
+func isFailure(want, got proto.Message, desc string) string {
+	m := new(mock)
+	u.Is(text(want), text(got), desc, m)
+	return m.output[0]
+}
+
+func TestProtoEqual(t *testing.T) {
+	m := new(mock)
+	five, six := wrapperspb.Int32(5), wrapperspb.Int32(6)
+
+	u.Is(true, protoeq.ProtoEqual(five, wrapperspb.Int32(5), "same", m),
+		"equal", t)
+	m.isOutput("equal out", t)
+
+	u.Is(false, protoeq.ProtoEqual(five, six, "num", m), "unequal", t)
+	m.isOutput("unequal out", t, isFailure(five, six, "num"))
+
+	u.Is(true, protoeq.ProtoEqual(nil, nil, "nils", m), "nils", t)
+	m.isOutput("nils out", t)
+	u.Is(false, protoeq.ProtoEqual(nil, five, "nil", m), "nil want", t)
+	m.isOutput("nil want out", t, isFailure(nil, five, "nil"))
+	u.Is(false, protoeq.ProtoEqual(five, nil, "nil", m), "nil got", t)
+	m.isOutput("nil got out", t, isFailure(five, nil, "nil"))
+
+	nan := structpb.NewNumberValue(math.NaN())
+	u.Is(true, protoeq.ProtoEqual(nan, structpb.NewNumberValue(math.NaN()),
+		"nan", m), "nan", t)
+	m.isOutput("nan out", t)
+
+	// The text form does not include the message type:
+	long := wrapperspb.Int64(5)
+	u.Is(false, protoeq.ProtoEqual(five, long, "type", m), "types", t)
+	m.isOutput("types out", t, "Got unequal message "+text(long)+" for type.")
+}
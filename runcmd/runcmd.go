@@ -0,0 +1,122 @@
+/*
+
+Package runcmd lets your tests run a command and then check what it wrote
+to stdout and stderr and its exit code:
+
+	import (
+		"testing"
+
+		"github.com/TyeMcQueen/go-tutl"
+		"github.com/TyeMcQueen/go-tutl/runcmd"
+	)
+
+	func TestVersion(t *testing.T) {
+		u := tutl.New(t)
+		out, errs, exit := runcmd.RunCommand(t, "./mytool", "--version")
+		u.Is(0, exit, "exit code")
+		u.Is("", errs, "stderr")
+		u.Like(out, "version output", `^mytool v\d+\.\d+`)
+	}
+
+This is a separate package so that go-tutl itself does not use "os/exec".
+
+*/
+package runcmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/TyeMcQueen/go-tutl"
+)
+
+// Options controls how RunCommand() runs commands.  The 'Default' global
+// is used by the non-method RunCommand().
+//
+type Options struct {
+	// Timeout is how long to let the command run before killing it.  A
+	// value of 0 (or less) means to never kill it.  It defaults to 1 minute.
+	//
+	Timeout time.Duration
+
+	// Dir is the directory to run the command in.  If empty, then the
+	// current directory is used.
+	//
+	Dir string
+
+	// Stdin is fed to the command as its standard input.  If empty, then
+	// the command's standard input is the null device.
+	//
+	Stdin string
+
+	// Env, if not 'nil', replaces the command's environment.  Each entry is
+	// of the form "key=value".
+	//
+	Env []string
+}
+
+// The 'runcmd.Default' global holds the Options used by RunCommand().
+var Default = Options{Timeout: time.Minute}
+
+// RunCommand() runs the command 'name' with the arguments 'args', waits
+// for it to finish, and returns what it wrote to stdout and to stderr and
+// its exit code.  'name' is found via exec.LookPath() if it does not
+// contain a path separator.
+//
+// A non-zero exit code is not a failure; check 'exit' yourself.  But if
+// the command can't be started, then a diagnostic similar to "Can't run
+// {name}: {error}" is displayed (which also causes the unit test to fail)
+// and 'exit' is -1.  If the command runs longer than Default.Timeout, then
+// it is killed, a diagnostic similar to "Killed {name} after timeout of
+// 1m0s." is displayed (which also causes the unit test to fail), and
+// 'exit' is -1.  In both cases, any output captured is still returned.
+//
+func RunCommand(
+	t tutl.TestingT, name string, args ...string,
+) (stdout, stderr string, exit int) {
+	t.Helper()
+	return Default.RunCommand(t, name, args...)
+}
+
+// See runcmd.RunCommand() for documentation.
+func (o Options) RunCommand(
+	t tutl.TestingT, name string, args ...string,
+) (stdout, stderr string, exit int) {
+	t.Helper()
+	ctx := context.Background()
+	if 0 < o.Timeout {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, o.Timeout)
+		defer cancel()
+	}
+	cmd := exec.CommandContext(ctx, name, args...)
+	// Don't wait forever for a killed command's children to close stdout:
+	cmd.WaitDelay = time.Second
+	cmd.Dir = o.Dir
+	cmd.Env = o.Env
+	if "" != o.Stdin {
+		cmd.Stdin = strings.NewReader(o.Stdin)
+	}
+	var out, errs bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &errs
+
+	err := cmd.Run()
+	stdout, stderr = out.String(), errs.String()
+	var ee *exec.ExitError
+	switch {
+	case nil != ctx.Err():
+		t.Errorf("Killed %s after timeout of %v.", name, o.Timeout)
+		return stdout, stderr, -1
+	case nil == err:
+		return stdout, stderr, 0
+	case errors.As(err, &ee):
+		return stdout, stderr, ee.ExitCode()
+	}
+	t.Errorf("Can't run %s: %v", name, err)
+	return stdout, stderr, -1
+}
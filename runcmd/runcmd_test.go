@@ -0,0 +1,83 @@
+package runcmd_test
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	u "github.com/TyeMcQueen/go-tutl"
+	"github.com/TyeMcQueen/go-tutl/runcmd"
+)
+
+type mock struct {
+	output []string
+}
+
+func (m *mock) Failed() bool { return false }
+func (m *mock) Helper()      {}
+
+func (m *mock) Error(args ...interface{}) { m.Log(args...) }
+
+func (m *mock) Errorf(format string, args ...interface{}) {
+	m.Logf(format, args...)
+}
+
+func (m *mock) Log(args ...interface{}) {
+	m.output = append(m.output, fmt.Sprint(args...))
+}
+
+func (m *mock) Logf(format string, args ...interface{}) {
+	m.output = append(m.output, fmt.Sprintf(format, args...))
+}
+
+func (m *mock) isOutput(desc string, t *testing.T, want ...string) {
+	t.Helper()
+	if u.Is(len(want), len(m.output), desc+" count", t) {
+		for i, o := range want {
+			u.Is(o, m.output[i], u.S(desc, " ", i), t)
+		}
+	} else {
+		t.Log("Surprise output:\n", strings.Join(m.output, "\n"))
+	}
+	m.output = nil
+}
+
+func TestRunCommand(t *testing.T) {
+	m := new(mock)
+
+	out, errs, exit := runcmd.RunCommand(m, "sh", "-c",
+		"echo out; echo err >&2; exit 3")
+	u.Is("out\n", out, "stdout", t)
+	u.Is("err\n", errs, "stderr", t)
+	u.Is(3, exit, "non-zero exit", t)
+	m.isOutput("non-zero exit out", t)
+
+	o := runcmd.Options{Stdin: "in", Dir: "/",
+		Env: []string{"X=1", "PATH=" + os.Getenv("PATH")}}
+	out, _, exit = o.RunCommand(m, "sh", "-c", `cat; echo " $X $PWD"`)
+	u.Is("in 1 /\n", out, "options", t)
+	u.Is(0, exit, "zero exit", t)
+	m.isOutput("options out", t)
+
+	_, _, exit = runcmd.RunCommand(m, "./no-such-command")
+	u.Is(-1, exit, "can't start", t)
+	if u.Is(1, len(m.output), "can't start count", t) {
+		u.Like(m.output[0], "can't start out", t,
+			"^Can't run ./no-such-command: ")
+	}
+	m.output = nil
+}
+
+func TestTimeout(t *testing.T) {
+	m := new(mock)
+	o := runcmd.Options{Timeout: time.Second / 10}
+
+	start := time.Now()
+	out, _, exit := o.RunCommand(m, "sh", "-c", "echo started; exec sleep 10")
+	u.Is(true, time.Since(start) < 5*time.Second, "killed early", t)
+	u.Is("started\n", out, "output kept", t)
+	u.Is(-1, exit, "timeout exit", t)
+	m.isOutput("timeout out", t, "Killed sh after timeout of 100ms.")
+}
@@ -0,0 +1,230 @@
+//go:build go1.21
+
+/*
+
+Package slogcap lets you capture structured log records (from "log/slog")
+in your tests and check that the expected ones were emitted:
+
+	import (
+		"log/slog"
+		"testing"
+
+		"github.com/TyeMcQueen/go-tutl/slogcap"
+	)
+
+	func TestLogin(t *testing.T) {
+		logs := slogcap.NewLogCapture()
+		svc := NewService(slog.New(logs))
+		svc.Login("bob")
+		slogcap.LogHas(logs, "login log", t,
+			slog.LevelInfo, "login", "user", "bob")
+	}
+
+This is a separate package so that using go-tutl does not require Go 1.21.
+
+*/
+package slogcap
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/TyeMcQueen/go-tutl"
+)
+
+// Record is one captured log record.  Attributes inside of groups are
+// stored under "."-separated keys such as "req.method".
+//
+type Record struct {
+	Level   slog.Level
+	Message string
+	Attrs   map[string]slog.Value
+}
+
+// String() formats a Record similar to `INFO "login" user=bob`.
+func (r Record) String() string {
+	keys := make([]string, 0, len(r.Attrs))
+	for k := range r.Attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := []string{r.Level.String(), tutl.DoubleQuote(r.Message)}
+	for _, k := range keys {
+		parts = append(parts, k+"="+r.Attrs[k].String())
+	}
+	return strings.Join(parts, " ")
+}
+
+// records holds the Records shared by a LogCapture and the handlers
+// derived from it via WithAttrs() and WithGroup().
+//
+type records struct {
+	mu   sync.Mutex
+	list []Record
+}
+
+// LogCapture is a slog.Handler that records every log record sent to it
+// (at any level).  Use NewLogCapture() to create one.  It is safe for
+// concurrent use.
+//
+type LogCapture struct {
+	recs   *records
+	prefix string
+	attrs  map[string]slog.Value
+}
+
+// NewLogCapture() returns a new LogCapture that has not captured anything.
+func NewLogCapture() *LogCapture {
+	return &LogCapture{recs: &records{}}
+}
+
+// Enabled() always returns 'true' so that records of every level are
+// captured.
+//
+func (c *LogCapture) Enabled(context.Context, slog.Level) bool {
+	return true
+}
+
+// Handle() records 'r'.
+func (c *LogCapture) Handle(_ context.Context, r slog.Record) error {
+	rec := Record{Level: r.Level, Message: r.Message,
+		Attrs: make(map[string]slog.Value, len(c.attrs)+r.NumAttrs())}
+	for k, v := range c.attrs {
+		rec.Attrs[k] = v
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		flatten(c.prefix, a, rec.Attrs)
+		return true
+	})
+	c.recs.mu.Lock()
+	defer c.recs.mu.Unlock()
+	c.recs.list = append(c.recs.list, rec)
+	return nil
+}
+
+// WithAttrs() returns a handler that adds 'attrs' to each record and that
+// shares its captured records with 'c'.
+//
+func (c *LogCapture) WithAttrs(attrs []slog.Attr) slog.Handler {
+	n := &LogCapture{recs: c.recs, prefix: c.prefix,
+		attrs: make(map[string]slog.Value, len(c.attrs)+len(attrs))}
+	for k, v := range c.attrs {
+		n.attrs[k] = v
+	}
+	for _, a := range attrs {
+		flatten(c.prefix, a, n.attrs)
+	}
+	return n
+}
+
+// WithGroup() returns a handler that puts later attributes into the group
+// 'name' and that shares its captured records with 'c'.
+//
+func (c *LogCapture) WithGroup(name string) slog.Handler {
+	if "" == name {
+		return c
+	}
+	return &LogCapture{recs: c.recs, prefix: c.prefix + name + ".",
+		attrs: c.attrs}
+}
+
+// Records() returns a copy of the records captured so far, in order.
+func (c *LogCapture) Records() []Record {
+	c.recs.mu.Lock()
+	defer c.recs.mu.Unlock()
+	return append([]Record(nil), c.recs.list...)
+}
+
+// flatten() stores 'a' into 'into', storing each attribute of a group
+// under its "."-separated key.
+//
+func flatten(prefix string, a slog.Attr, into map[string]slog.Value) {
+	v := a.Value.Resolve()
+	if slog.KindGroup != v.Kind() {
+		if "" != a.Key {
+			into[prefix+a.Key] = v
+		}
+		return
+	}
+	if "" != a.Key {
+		prefix += a.Key + "."
+	}
+	for _, sub := range v.Group() {
+		flatten(prefix, sub, into)
+	}
+}
+
+// LogHas() tests that 'capture' recorded at least one record with the
+// given 'level' and 'msg' that also has each of the attributes in 'attrs'.
+// 'attrs' holds pairs of arguments, a 'string' key followed by the wanted
+// value, just like the arguments to slog.Info() (but slog.Attr values are
+// also allowed).  Keys of attributes inside of groups are given with "."
+// separators, such as "req.method".  Values are compared via tutl.V() and
+// other attributes of the record are ignored:
+//
+//      slogcap.LogHas(logs, "retry log", t,
+//          slog.LevelWarn, "retrying", "attempt", 2, "req.method", "GET")
+//
+// If no such record was captured, then a diagnostic similar to "No log
+// record like {record} for {desc}." is displayed (which also causes the
+// unit test to fail) and each captured record is logged.
+//
+// LogHas() returns whether the test passed.
+//
+func LogHas(
+	capture *LogCapture, desc string, t tutl.TestingT,
+	level slog.Level, msg string, attrs ...interface{},
+) bool {
+	t.Helper()
+	want := Record{Level: level, Message: msg,
+		Attrs: make(map[string]slog.Value)}
+	for 0 < len(attrs) {
+		switch a := attrs[0].(type) {
+		case slog.Attr:
+			flatten("", a, want.Attrs)
+			attrs = attrs[1:]
+		case string:
+			if len(attrs) < 2 {
+				t.Errorf("LogHas() got key %q without a value for %s.",
+					a, desc)
+				return false
+			}
+			want.Attrs[a] = slog.AnyValue(attrs[1]).Resolve()
+			attrs = attrs[2:]
+		default:
+			t.Errorf("LogHas() needs a string key not %T for %s.", a, desc)
+			return false
+		}
+	}
+
+	got := capture.Records()
+	for _, rec := range got {
+		if matches(want, rec) {
+			return true
+		}
+	}
+	t.Errorf("No log record like %s for %s.", want, desc)
+	for _, rec := range got {
+		t.Logf("    captured: %s", rec)
+	}
+	return false
+}
+
+// matches() returns whether 'rec' has the level, message, and attributes
+// of 'want'.
+//
+func matches(want, rec Record) bool {
+	if want.Level != rec.Level || want.Message != rec.Message {
+		return false
+	}
+	for k, v := range want.Attrs {
+		got, ok := rec.Attrs[k]
+		if !ok || tutl.V(v.Any()) != tutl.V(got.Any()) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,113 @@
+//go:build go1.21
+
+package slogcap_test
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+
+	u "github.com/TyeMcQueen/go-tutl"
+	"github.com/TyeMcQueen/go-tutl/slogcap"
+)
+
+type mock struct {
+	output []string
+}
+
+func (m *mock) Failed() bool { return false }
+func (m *mock) Helper()      {}
+
+func (m *mock) Error(args ...interface{}) { m.Log(args...) }
+
+func (m *mock) Errorf(format string, args ...interface{}) {
+	m.Logf(format, args...)
+}
+
+func (m *mock) Log(args ...interface{}) {
+	m.output = append(m.output, fmt.Sprint(args...))
+}
+
+func (m *mock) Logf(format string, args ...interface{}) {
+	m.output = append(m.output, fmt.Sprintf(format, args...))
+}
+
+func (m *mock) isOutput(desc string, t *testing.T, want ...string) {
+	t.Helper()
+	if u.Is(len(want), len(m.output), desc+" count", t) {
+		for i, o := range want {
+			u.Is(o, m.output[i], u.S(desc, " ", i), t)
+		}
+	} else {
+		t.Log("Surprise output:\n", strings.Join(m.output, "\n"))
+	}
+	m.output = nil
+}
+
+func TestLogCapture(t *testing.T) {
+	logs := slogcap.NewLogCapture()
+	base := slog.New(logs)
+	req := base.With("svc", "api").WithGroup("req").With("id", 7)
+	req.Info("start", "method", "GET", slog.Group("user", "name", "bo"))
+	base.WithGroup("").Debug("plain")
+	base.Warn("grouped", slog.Group("", "inline", true))
+
+	recs := logs.Records()
+	if !u.Is(3, len(recs), "shared records", t) {
+		return
+	}
+	u.Is(`INFO "start" req.id=7 req.method=GET req.user.name=bo svc=api`,
+		recs[0], "flattened", t)
+	u.Is(`DEBUG "plain"`, recs[1], "empty group", t)
+	u.Is(`WARN "grouped" inline=true`, recs[2], "inline group", t)
+
+	req.Info("again")
+	u.Is(4, len(logs.Records()), "derived handler shares records", t)
+	u.Is(3, len(recs), "Records() is a copy", t)
+}
+
+func TestLogHas(t *testing.T) {
+	m := new(mock)
+	logs := slogcap.NewLogCapture()
+	log := slog.New(logs)
+	log.With("svc", "api").WithGroup("req").Info("start",
+		"id", 7, "method", "GET")
+	log.Warn("retry", "n", 2)
+
+	u.Is(true, slogcap.LogHas(logs, "kv", m, slog.LevelInfo, "start",
+		"req.id", 7, "svc", "api"), "key/value", t)
+	u.Is(true, slogcap.LogHas(logs, "attr", m, slog.LevelInfo, "start",
+		slog.Group("req", "method", "GET"), slog.Int64("req.id", 7)),
+		"slog.Attr", t)
+	u.Is(true, slogcap.LogHas(logs, "V", m, slog.LevelWarn, "retry",
+		"n", "2"), "compared via V()", t)
+	u.Is(true, slogcap.LogHas(logs, "none", m, slog.LevelWarn, "retry"),
+		"no attrs", t)
+	m.isOutput("pass out", t)
+
+	u.Is(false, slogcap.LogHas(logs, "bad", m, slog.LevelWarn, "retry",
+		"n", 3), "wrong value", t)
+	m.isOutput("wrong value out", t,
+		`No log record like WARN "retry" n=3 for bad.`,
+		`    captured: INFO "start" req.id=7 req.method=GET svc=api`,
+		`    captured: WARN "retry" n=2`)
+	u.Is(false, slogcap.LogHas(logs, "lvl", m, slog.LevelError, "retry"),
+		"wrong level", t)
+	u.Is(3, len(m.output), "wrong level count", t)
+	u.Is(`No log record like ERROR "retry" for lvl.`, m.output[0],
+		"wrong level out", t)
+	m.output = nil
+	u.Is(false, slogcap.LogHas(logs, "gone", m, slog.LevelWarn, "retry",
+		"missing", nil), "missing attr", t)
+	m.output = nil
+
+	u.Is(false, slogcap.LogHas(logs, "dangle", m, slog.LevelWarn, "retry",
+		"n"), "dangling key", t)
+	m.isOutput("dangling key out", t,
+		`LogHas() got key "n" without a value for dangle.`)
+	u.Is(false, slogcap.LogHas(logs, "nonstr", m, slog.LevelWarn, "retry",
+		1, 2), "non-string key", t)
+	m.isOutput("non-string key out", t,
+		"LogHas() needs a string key not int for nonstr.")
+}
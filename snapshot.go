@@ -0,0 +1,126 @@
+package tutl
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+)
+
+// SnapshotValue() compares 'got' to a snapshot of it that was saved to a
+// file by a prior test run.  This locks down complex results (not just
+// strings) against regressions:
+//
+//      u.SnapshotValue("parse-order", ParseOrder(input), t)
+//
+// 'got' is converted to JSON via json.MarshalIndent() (map keys are sorted
+// so the result is deterministic) and stored in the file named 'name' plus
+// ".json" in the directory given by Options.SnapshotDir ("testdata" by
+// default).  The snapshot file is compared as JSON, so its formatting (and
+// the order of object keys) does not matter.
+//
+// If 'go test' was given the '-update' flag, then the snapshot file is
+// written (creating its directory if needed) from 'got' and the test
+// passes:
+//
+//      go test ./... -update
+//
+// But tutl does not define the '-update' flag itself, since a package that
+// already defines it (a common idiom for golden files) would then panic
+// with "flag redefined".  So your test package must define it (if it does
+// not already) for this to work:
+//
+//      var _ = flag.Bool("update", false, "update snapshot files")
+//
+// Setting the environment variable TUTL_UPDATE_SNAPSHOTS to a non-empty
+// value also updates the snapshot files, without the flag being defined:
+//
+//      TUTL_UPDATE_SNAPSHOTS=1 go test ./...
+//
+// Otherwise, if the snapshot file does not exist, then a diagnostic
+// similar to "Can't read snapshot {name}: {error}" is displayed (which
+// also causes the unit test to fail).  If 'got' does not match the
+// snapshot, then each difference is reported via a diagnostic that
+// includes the path to the value [just like IsJsonNear()], such as "Got 3
+// not 2 at items.0.qty for snapshot {name}.", which also causes the unit
+// test to fail.
+//
+// SnapshotValue() returns whether the test passed.
+//
+func SnapshotValue(name string, got interface{}, t TestingT) bool {
+	t.Helper()
+	return Default.SnapshotValue(name, got, t)
+}
+
+// See tutl.SnapshotValue() for documentation.
+func (o Options) SnapshotValue(
+	name string, got interface{}, t TestingT,
+) (passed bool) {
+	t.Helper()
+	desc := "snapshot " + name
+	defer o.hooks(desc)(&passed)
+	desc = o.descOf(desc)
+	j, err := json.MarshalIndent(got, "", "    ")
+	if nil != err {
+		t.Errorf("Can't convert %T to JSON for %s: %v", got, desc, err)
+		return false
+	}
+	j = append(j, '\n')
+
+	path := o.snapshotPath(name)
+	if updateSnapshots() {
+		err = os.MkdirAll(filepath.Dir(path), 0o777)
+		if nil == err {
+			err = os.WriteFile(path, j, 0o666)
+		}
+		if nil != err {
+			t.Errorf("Can't update %s: %v", desc, err)
+			return false
+		}
+		t.Logf("Updated %s (%s).", desc, path)
+		return true
+	}
+
+	saved, err := os.ReadFile(path)
+	if nil != err {
+		t.Errorf("Can't read %s: %v", desc, err)
+		return false
+	}
+	wDoc, err := fromJson(saved)
+	if nil != err {
+		t.Errorf("Invalid JSON in %s for %s: %v", path, desc, err)
+		return false
+	}
+	gDoc, err := fromJson(j)
+	if nil != err {
+		t.Errorf("Invalid JSON for %s: %v", desc, err)
+		return false
+	}
+	if snapshot(wDoc) == snapshot(gDoc) {
+		return true
+	}
+	lim := o.limitFailures(t)
+	jsonNear("", wDoc, gDoc, 0, desc, lim)
+	lim.done()
+	return false
+}
+
+// snapshotPath() returns the file that holds the snapshot called 'name'.
+func (o Options) snapshotPath(name string) string {
+	dir := o.SnapshotDir
+	if "" == dir {
+		dir = "testdata"
+	}
+	return filepath.Join(dir, name+".json")
+}
+
+// updateSnapshots() returns whether SnapshotValue() should write snapshot
+// files rather than compare against them.
+//
+func updateSnapshots() bool {
+	if "" != os.Getenv("TUTL_UPDATE_SNAPSHOTS") {
+		return true
+	}
+	f := flag.Lookup("update")
+	return nil != f && "true" == f.Value.String()
+}
@@ -0,0 +1,209 @@
+package tutl
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// StructDiff() compares 'want' and 'got' field by field, reporting every
+// difference found rather than just the first.  It recurses into nested
+// structs, maps, slices, arrays, pointers, and interfaces.  Each
+// difference is reported via a diagnostic similar to "Got {got} not {want}
+// at {path} for {desc}." (which also causes the unit test to fail).
+//
+// {path} uses the same "."-separated syntax as FieldType(), such as
+// "DB.Hosts.0.Port", where struct fields are given by name, map entries
+// by key, and slice or array elements by index.  A map key present in
+// only one of the maps is shown with a value of "(missing)".  Slices of
+// different lengths are reported once and then only their common elements
+// are compared.  Leaf values are compared via V() and shown via S().
+//
+// Unexported struct fields are not compared; a note similar to "Skipped
+// unexported field {path} for {desc}." is logged for each one.  Values
+// nested more deeply than Options.MaxDepth are not compared either; a note
+// similar to "Max depth (50) exceeded at {path} for {desc}." is logged.
+//
+// A pointer that leads back to a value that is already being compared
+// (a cycle, such as in a circular linked list) is not followed again; a
+// note similar to "Cycle detected at {path} for {desc}." is logged.  Only
+// cycles through pointers are detected (a cycle only through maps or
+// slices is still stopped by MaxDepth).
+//
+// StructDiff() returns the number of differences reported.
+//
+func StructDiff(want, got interface{}, desc string, t TestingT) int {
+	t.Helper()
+	return Default.StructDiff(want, got, desc, t)
+}
+
+// See tutl.StructDiff() for documentation.
+func (o Options) StructDiff(
+	want, got interface{}, desc string, t TestingT,
+) (failures int) {
+	t.Helper()
+	defer o.hooksN(desc)(&failures)
+	desc = o.descOf(desc)
+	lim := o.limitFailures(t)
+	defer lim.done()
+	d := structDiffer{o: o, desc: desc, t: lim}
+	d.diff("", reflect.ValueOf(want), reflect.ValueOf(got))
+	return d.failures
+}
+
+// structDiffer holds the state of one StructDiff() call.
+type structDiffer struct {
+	o        Options
+	desc     string
+	t        TestingT
+	failures int
+	depth    int
+	visiting map[visit]bool
+}
+
+// visit identifies a pair of pointers being compared by StructDiff().
+type visit struct {
+	w, g uintptr
+	typ  reflect.Type
+}
+
+// report() reports one difference found at 'path'.
+func (d *structDiffer) report(path, sWant, sGot string) {
+	d.t.Helper()
+	d.failures++
+	if "" == path {
+		d.o.gotNot(sGot, sWant, d.desc, d.t)
+		return
+	}
+	d.t.Errorf("Got %s not %s at %s for %s.",
+		d.o.ReplaceNewlines(sGot), d.o.ReplaceNewlines(sWant), path, d.desc)
+}
+
+// show() returns how to display a value found while walking.
+func (d *structDiffer) show(v reflect.Value) string {
+	if !v.IsValid() {
+		return d.o.S(nil)
+	}
+	return d.o.S(v.Interface())
+}
+
+// subPath() appends 'key' to 'path'.
+func subPath(path, key string) string {
+	if "" == path {
+		return key
+	}
+	return path + "." + key
+}
+
+// diff() reports each difference between 'w' and 'g' found at 'path'.
+func (d *structDiffer) diff(path string, w, g reflect.Value) {
+	d.t.Helper()
+	if !w.IsValid() || !g.IsValid() {
+		if w.IsValid() != g.IsValid() {
+			d.report(path, d.show(w), d.show(g))
+		}
+		return
+	}
+	if w.Type() != g.Type() {
+		d.report(path, d.show(w), d.show(g))
+		return
+	}
+	if max := d.o.maxDepth(); 0 < max && max <= d.depth && nested(w) {
+		d.t.Logf("Max depth (%d) exceeded at %s for %s.",
+			max, orTop(path), d.desc)
+		return
+	}
+	d.depth++
+	defer func() { d.depth-- }()
+	switch w.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if w.IsNil() || g.IsNil() {
+			if w.IsNil() != g.IsNil() {
+				d.report(path, d.show(w), d.show(g))
+			}
+			return
+		}
+		if reflect.Ptr == w.Kind() {
+			v := visit{w.Pointer(), g.Pointer(), w.Type()}
+			if d.visiting[v] {
+				d.t.Logf("Cycle detected at %s for %s.", orTop(path), d.desc)
+				return
+			}
+			if nil == d.visiting {
+				d.visiting = make(map[visit]bool)
+			}
+			d.visiting[v] = true
+			defer delete(d.visiting, v)
+		}
+		d.diff(path, w.Elem(), g.Elem())
+	case reflect.Struct:
+		for i := 0; i < w.NumField(); i++ {
+			f := w.Type().Field(i)
+			if "" != f.PkgPath {
+				d.t.Logf("Skipped unexported field %s for %s.",
+					subPath(path, f.Name), d.desc)
+				continue
+			}
+			d.diff(subPath(path, f.Name), w.Field(i), g.Field(i))
+		}
+	case reflect.Map:
+		d.diffMaps(path, w, g)
+	case reflect.Slice, reflect.Array:
+		if w.Len() != g.Len() {
+			d.failures++
+			d.t.Errorf("Got length %d not %d at %s for %s.",
+				g.Len(), w.Len(), orTop(path), d.desc)
+		}
+		for i := 0; i < w.Len() && i < g.Len(); i++ {
+			d.diff(subPath(path, fmt.Sprint(i)), w.Index(i), g.Index(i))
+		}
+	default:
+		if d.o.V(w.Interface()) != d.o.V(g.Interface()) {
+			d.report(path, d.show(w), d.show(g))
+		}
+	}
+}
+
+// diffMaps() reports each difference between the maps 'w' and 'g'.
+func (d *structDiffer) diffMaps(path string, w, g reflect.Value) {
+	d.t.Helper()
+	keys := w.MapKeys()
+	for _, k := range g.MapKeys() {
+		if !w.MapIndex(k).IsValid() {
+			keys = append(keys, k)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j])
+	})
+	for _, k := range keys {
+		sub := subPath(path, fmt.Sprint(k))
+		wv, gv := w.MapIndex(k), g.MapIndex(k)
+		switch {
+		case !wv.IsValid():
+			d.report(sub, missingCell, d.show(gv))
+		case !gv.IsValid():
+			d.report(sub, d.show(wv), missingCell)
+		default:
+			d.diff(sub, wv, gv)
+		}
+	}
+}
+
+// nested() returns whether 'v' is of a kind that can contain other values.
+func nested(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Struct, reflect.Map,
+		reflect.Slice, reflect.Array:
+		return true
+	}
+	return false
+}
+
+// orTop() returns 'path' or, if it is empty, "top level".
+func orTop(path string) string {
+	if "" == path {
+		return "top level"
+	}
+	return path
+}
@@ -0,0 +1,29 @@
+package tutl
+
+import (
+	"time"
+)
+
+// TimeFormatIs() formats 'got' using 'layout' [see time.Time.Format()] and
+// tests that the result equals 'want' just like Is() does.  This tests
+// how a time is presented rather than what instant it is:
+//
+//      u.TimeFormatIs("2024-03-01", inv.DueDate, time.DateOnly, "due", t)
+//
+// TimeFormatIs() returns whether the test passed.
+//
+func TimeFormatIs(
+	want string, got time.Time, layout string, desc string, t TestingT,
+) bool {
+	t.Helper()
+	return Default.TimeFormatIs(want, got, layout, desc, t)
+}
+
+// See tutl.TimeFormatIs() for documentation.
+func (o Options) TimeFormatIs(
+	want string, got time.Time, layout string, desc string, t TestingT,
+) (passed bool) {
+	t.Helper()
+	defer o.hooks(desc)(&passed)
+	return o.noHooks().Is(want, got.Format(layout), desc, t)
+}
@@ -1,15 +1,25 @@
 package tutl_test
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"io"
+	"math"
 	"os"
+	"path/filepath"
+	"runtime"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	u "github.com/TyeMcQueen/go-tutl"
 )
 
+// Lets SnapshotValue() updates be requested via "go test -update".
+var _ = flag.Bool("update", false, "update snapshot files")
+
 func TestMain(m *testing.M) {
 	go u.ShowStackOnInterrupt()
 	os.Exit(m.Run())
@@ -118,7 +128,7 @@ type mock struct {
 	output []string
 }
 
-func (m *mock) Failed() bool { return false }
+func (m *mock) Failed() bool { return 0 < m.fails }
 func (m *mock) Helper()      {}
 func (m *mock) clear()       { m.output = m.output[:0]; m.fails = 0 }
 
@@ -290,3 +300,1944 @@ func TestOutput(t *testing.T) {
 	u.Is(false, s.Is(5, 2+2, "math joke"), "joke is false", t)
 	m.isOutput("joke out", t, "\nGot 4\nnot 5\nfor math joke.")
 }
+
+func TestCircaTruncates(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+
+	u.Is(false, s.Circa(4, 1.2345, 1.2346, "round"), "round straddle", t)
+	m.isOutput("round straddle out", t, "Got 1.235 not 1.234 for round.")
+	u.Is(true, s.Circa(4, 1.2349, 1.2351, "round"), "round near", t)
+
+	s.SetCircaTruncates(true)
+	u.Is(true, s.Circa(4, 1.2345, 1.2346, "trunc"), "trunc same", t)
+	u.Is(true, s.Circa(1, -1.99, -1.01, "trunc"), "trunc 1 digit", t)
+	u.Is(true, s.Circa(3, 12345, 12399, "trunc"), "trunc int", t)
+	m.isOutput("trunc out", t)
+	u.Is(false, s.Circa(4, 1.2349, 1.2351, "trunc"), "trunc differ", t)
+	m.isOutput("trunc differ out", t, "Got 1.235 not 1.234 for trunc.")
+	u.Is(false, s.Circa(4, math.Inf(1), math.NaN(), "odd"), "inf", t)
+	m.isOutput("inf out", t, "Got NaN not +Inf for odd.")
+}
+
+func TestBoolWords(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+
+	u.Is("true", s.S(true), "default", t)
+	s.SetBoolWords("disabled", "enabled")
+	u.Is("enabled disabled", s.S(true, " ", false), "words", t)
+	u.Is("true", s.V(true), "V unchanged", t)
+	u.Is(true, s.Is(true, "true", "compare"), "compare unchanged", t)
+	u.Is(false, s.Is(false, true, "flag"), "differ", t)
+	m.isOutput("differ out", t, "Got enabled not disabled for flag.")
+	s.SetBoolWords("", "yes")
+	u.Is("false yes", s.S(false, " ", true), "empty word", t)
+}
+
+func TestThousandsSep(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+
+	u.Is("1048576", s.S(1048576), "no separator by default", t)
+	s.SetThousandsSep(',')
+	u.Is("1,048,576", s.S(1048576), "comma separator", t)
+	u.Is("-123,456", s.S(-123456), "negative", t)
+	u.Is("999", s.S(999), "short", t)
+	u.Is("'x'", s.S("x"[0]), "byte not separated", t)
+	u.Is("1048576", s.V(1048576), "V unchanged", t)
+	s.SetThousandsSep('_')
+	u.Is("12_345", s.S(uint64(12345)), "underscore separator", t)
+
+	u.Is(true, s.Is(1000000, 1000000, "equal"), "separated equal", t)
+	m.isOutput("separated equal out", t)
+	u.Is(false, s.Is(1000000, 1000001, "count"), "separated differ", t)
+	m.isOutput("separated differ out", t,
+		"Got 1_000_001 not 1_000_000 for count.")
+}
+
+func TestHumanBytes(t *testing.T) {
+	u.Is("0 B", u.HumanBytes(0), "zero", t)
+	u.Is("1023 B", u.HumanBytes(1023), "just under KiB", t)
+	u.Is("1 KiB", u.HumanBytes(1024), "KiB", t)
+	u.Is("1.5 MiB", u.HumanBytes(1572864), "1.5 MiB", t)
+	u.Is("1.46 MiB", u.HumanBytes(1532000), "rounded MiB", t)
+	u.Is("-2 GiB", u.HumanBytes(-2<<30), "negative", t)
+	u.Is("-8 EiB", u.HumanBytes(-1<<63), "min int64", t)
+
+	m := new(mock)
+	s := u.New(m)
+	u.Is(false, s.Is(u.Bytes(1<<20), u.Bytes(1572864), "size"), "bytes", t)
+	m.isOutput("bytes out", t, "Got 1.5 MiB not 1 MiB for size.")
+	u.Is(false, s.Is(u.Bytes(1572864), u.Bytes(1572865), "size"), "close", t)
+	m.isOutput("close out", t,
+		"\nGot 1.5 MiB (1572865) not 1.5 MiB (1572864) for size.")
+	u.Is("1572864", s.V(u.Bytes(1572864)), "V bytes raw", t)
+	s.SetHumanizeBytes(false)
+	u.Is("1572864", s.S(u.Bytes(1572864)), "S bytes not humanized", t)
+}
+
+func TestSameFunc(t *testing.T) {
+	m := new(mock)
+	double := func(i int) int { return 2 * i }
+	shift := func(i int) int { return i << 1 }
+	square := func(i int) int { return i * i }
+
+	u.Is(0, u.SameFunc(m, []int{-3, 0, 1, 7}, double, shift), "same", t)
+	m.isOutput("same out", t)
+	u.Is(2, u.SameFunc(m, []int{-3, 0, 2, 7}, double, square), "differ", t)
+	m.isOutput("differ out", t,
+		"Got 9 not -6 for input -3.",
+		"Got 49 not 14 for input 7.")
+}
+
+func TestMaxDescLen(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+
+	long := "GET /api/v1/accounts/12345/settings"
+	s.Is(200, 404, long)
+	m.isOutput("no truncation", t,
+		"\nGot 404 not 200 for GET /api/v1/accounts/12345/settings.")
+	s.SetMaxDescLen(16)
+	s.Is(200, 404, long)
+	m.isOutput("truncated", t,
+		"Got 404 not 200 for GET /api/v1/a....",
+		"(Full desc: GET /api/v1/accounts/12345/settings)")
+	s.IsNot(200, 200, long)
+	m.isOutput("IsNot truncated", t,
+		"Got unwanted 200 for GET /api/v1/a....",
+		"(Full desc: GET /api/v1/accounts/12345/settings)")
+	s.Is(1, 2, "short desc")
+	m.isOutput("short not truncated", t, "Got 2 not 1 for short desc.")
+}
+
+func TestVerbose(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+
+	s.Is(4, 2+2, "sum")
+	m.isOutput("quiet by default", t)
+	s.SetVerbose(true)
+	s.Is(4, 2+2, "sum")
+	s.IsNot(5, 2+2, "not a joke")
+	s.HasType("int", 4, "type")
+	s.Circa(3, 1.2345, 1.2346, "circa")
+	u.Is(0, m.fails, "verbose passes are not failures", t)
+	m.isOutput("verbose out", t,
+		"OK: Got 4 for sum.",
+		"OK: Got 4 not 5 for not a joke.",
+		`OK: Got "int" for type.`,
+		"OK: Got 1.23 for circa.")
+}
+
+func TestKeyCount(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+
+	type pt struct {
+		X, Y int
+		z    int
+	}
+	u.Is(true, s.KeyCount(2, map[string]int{"a": 1, "b": 2}, "map"), "map", t)
+	u.Is(true, s.KeyCount(2, pt{}, "struct"), "struct", t)
+	u.Is(true, s.KeyCount(2, &pt{}, "*struct"), "*struct", t)
+	u.Is(true, s.KeyCount(1, `{"id":7}`, "json"), "json", t)
+	m.isOutput("key counts match", t)
+
+	u.Is(false, s.KeyCount(2, []byte(`{"c":1,"a":2,"b":3}`), "obj"), "bad", t)
+	m.isOutput("key count mismatch", t, "Got 3 keys (a, b, c) not 2 for obj.")
+	u.Is(false, s.KeyCount(1, 7, "int"), "not countable", t)
+	m.likeOutput("not countable out", t,
+		"*can't count keys for int", "*need a map", ` not int\s*$`)
+	u.Is(false, s.KeyCount(1, "[1]", "array"), "json array", t)
+	m.likeOutput("json array out", t, "*can't count keys for array")
+}
+
+func TestToStruct(t *testing.T) {
+	m := new(mock)
+	type resp struct {
+		Status int    `json:"status"`
+		Msg    string `json:"msg"`
+	}
+
+	r := u.ToStruct[resp](`{"status":200,"msg":"OK"}`, m)
+	u.Is(200, r.Status, "status from JSON", t)
+	u.Is("OK", r.Msg, "msg from JSON", t)
+	r = u.ToStruct[resp](map[string]interface{}{"status": 404}, m)
+	u.Is(404, r.Status, "status from map", t)
+	m.isOutput("no conversion errors", t)
+
+	r = u.ToStruct[resp]([]byte(`{"status":"bad"}`), m)
+	u.Is(0, r.Status, "zero on error", t)
+	m.likeOutput("unmarshal error", t, "*can't convert JSON to tutl_test.resp")
+	u.ToStruct[resp](make(chan int), m)
+	m.likeOutput("marshal error", t, "*can't convert chan int to JSON")
+}
+
+func TestIsUTF8(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+
+	u.Is(true, s.IsUTF8("café", "valid"), "valid", t)
+	u.Is(true, s.IsUTF8([]byte(""), "empty"), "empty", t)
+	m.isOutput("valid out", t)
+	u.Is(false, s.IsUTF8("ab\xFFc", "invalid"), "invalid", t)
+	m.isOutput("invalid out", t,
+		`Got invalid UTF-8 (\xFF) at byte 2 of "ab\xFFc" for invalid.`)
+	u.Is(false, s.IsUTF8([]byte("é\xC3"), "truncated"), "truncated", t)
+	m.isOutput("truncated out", t,
+		`Got invalid UTF-8 (\xC3) at byte 2 of "`+"é"+`\xC3" for truncated.`)
+}
+
+type badReader struct{}
+
+func (badReader) Read([]byte) (int, error) { return 0, fmt.Errorf("disk on fire") }
+
+func TestIsReaderString(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+
+	u.Is(true, s.IsReaderString("hi\n", strings.NewReader("hi\n"), "same"),
+		"same", t)
+	m.isOutput("same out", t)
+	u.Is(false, s.IsReaderString("hi", strings.NewReader("bye"), "diff"),
+		"diff", t)
+	m.isOutput("diff out", t, `Got "bye" not "hi" for diff.`)
+	u.Is(false, s.IsReaderString("hi", badReader{}, "bad"), "bad", t)
+	m.isOutput("bad out", t, "Error reading bad: disk on fire")
+}
+
+func TestGroup(t *testing.T) {
+	m := new(mock)
+
+	u.Is(true, u.Group(m, func(g u.TUTL) {
+		g.Is(1, 1, "one")
+		g.Like("hello", "greeting", "*ell")
+	}), "passing group", t)
+	m.isOutput("passing group out", t)
+
+	s := u.New(m)
+	s.SetThousandsSep(',')
+	u.Is(false, s.Group(func(g u.TUTL) {
+		g.Is(1000, 1000, "thousand")
+		g.Is(1000, 1001, "off by one")
+		g.Is(2, 2, "two")
+	}), "failing group", t)
+	m.isOutput("failing group out", t,
+		"Got 1,001 not 1,000 for off by one.")
+}
+
+func TestFieldType(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+	doc := `{"id":"x7","n":3,"ok":true,"tags":["a"],"sub":{"v":null}}`
+
+	u.Is(true, s.FieldType("string", doc, "id", "id"), "id", t)
+	u.Is(true, s.FieldType("number", doc, "n", "n"), "n", t)
+	u.Is(true, s.FieldType("bool", doc, "ok", "ok"), "ok", t)
+	u.Is(true, s.FieldType("array", doc, "tags", "tags"), "tags", t)
+	u.Is(true, s.FieldType("string", doc, "tags.0", "tag"), "tags.0", t)
+	u.Is(true, s.FieldType("object", doc, "sub", "sub"), "sub", t)
+	u.Is(true, s.FieldType("null", doc, "sub.v", "v"), "sub.v", t)
+	u.Is(true, s.FieldType("number", struct{ ID int }{7}, "ID", "s"), "ID", t)
+	m.isOutput("field types match", t)
+
+	u.Is(false, s.FieldType("number", doc, "id", "resp"), "wrong type", t)
+	m.isOutput("wrong type out", t, "Got string not number at id for resp.")
+	u.Is(false, s.FieldType("number", doc, "tags.1", "resp"), "missing", t)
+	m.isOutput("missing out", t, "No tags.1 found for resp.")
+	u.Is(false, s.FieldType("number", "{", "id", "resp"), "bad JSON", t)
+	m.likeOutput("bad JSON out", t, "*invalid JSON for resp: ")
+}
+
+func TestElement(t *testing.T) {
+	m := new(mock)
+
+	doc := `{"config": {"b": {"on": false, "n": [1, 2]}, "a": {"on": true}},
+		"list": [{"id": 1}, {"id": 2}]}`
+	u.Is(true, u.Element(doc, "config.a.on", m), "plain", t)
+	u.Is("[1 2]", u.Element(doc, "list.*.id", m), "array", t)
+	u.Is("[true false]", u.Element(doc, "config.*.on", m), "object", t)
+	u.Is(2, len(u.Element(doc, "list.*", m).([]interface{})), "last", t)
+	u.Is("[[1 2]]", u.Element(`[{"n": [{"x": 1}, {"x": 2}]}]`, "*.n.*.x", m),
+		"nested", t)
+	m.isOutput("found out", t)
+
+	u.Is(nil, u.Element(doc, "config.*.n", m), "partial", t)
+	m.isOutput("partial out", t, "No config.*.n found.")
+	u.Is(nil, u.Element(doc, "config.a.on.*", m), "not container", t)
+	m.isOutput("not container out", t, "No config.a.on.* found.")
+	u.Is(nil, u.Element("{", "a", m), "invalid", t)
+	m.likeOutput("invalid out", t, "^Invalid JSON: ")
+
+	s := u.New(m)
+	u.Is(true, s.FieldType("array", doc, "list.*", "wild"), "FieldType", t)
+	u.Is(0, s.HasMap("wild", doc, u.M("list.*.id", []int{1, 2})), "HasMap", t)
+	u.Is("[1 2]", s.Element(doc, "list.*.id"), "method", t)
+	m.isOutput("others out", t)
+}
+
+func TestNearULP(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+	one := 1.0
+	next := math.Nextafter(one, 2)
+	nan := math.NaN()
+	inf := math.Inf(1)
+
+	u.Is(true, s.NearULP(one, one, 0, "same"), "same", t)
+	u.Is(true, s.NearULP(one, next, 1, "next"), "next", t)
+	u.Is(true, s.NearULP(0, math.Copysign(0, -1), 0, "zeros"), "zeros", t)
+	u.Is(true, s.NearULP(-5e-324, 5e-324, 2, "tiny"), "across zero", t)
+	u.Is(true, s.NearULP(nan, nan, 0, "NaNs"), "NaNs", t)
+	u.Is(true, s.NearULP(inf, inf, 0, "infs"), "infs", t)
+	m.isOutput("near out", t)
+
+	u.Is(false, s.NearULP(one, next, 0, "next"), "next too far", t)
+	m.isOutput("next out", t,
+		"Got 1.0000000000000002 not 1 (off by 1 ULPs) for next.")
+	u.Is(false, s.NearULP(one, nan, 5, "nan"), "nan not near", t)
+	m.isOutput("nan out", t, "Got NaN not 1 for nan.")
+	u.Is(false, s.NearULP(inf, math.MaxFloat64, 5, "inf"), "inf", t)
+	m.isOutput("inf out", t, "Got 1.7976931348623157e+308 not +Inf for inf.")
+	u.Is(false, s.NearULP(-inf, inf, 5, "infs"), "opposite infs", t)
+	m.isOutput("infs out", t, "Got +Inf not -Inf for infs.")
+	u.Is(false, s.NearULP(-math.MaxFloat64, math.MaxFloat64, 5, "max"),
+		"huge distance", t)
+	m.isOutput("max out", t, "Got 1.7976931348623157e+308 not "+
+		"-1.7976931348623157e+308 (off by 18437736874454810622 ULPs) for max.")
+}
+
+func TestDescTransform(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+	s.SetDescTransform(func(d string) string {
+		return strings.TrimPrefix(d, "/very/long/prefix")
+	})
+
+	s.Is(1, 2, "/very/long/prefix/a.txt")
+	m.isOutput("Is out", t, "Got 2 not 1 for /a.txt.")
+	s.SetMaxDescLen(6)
+	s.Is(1, 2, "/very/long/prefix/abc.txt")
+	m.isOutput("Is truncated out", t,
+		"Got 2 not 1 for /ab....",
+		"(Full desc: /abc.txt)")
+	s.SetMaxDescLen(0)
+	s.Like("hi", "/very/long/prefix/b.txt", "*bye")
+	m.isOutput("Like out", t, "No <bye>...", "In <hi> for /b.txt.")
+}
+
+func TestIsEmpty(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+	var nilSlice []int
+	var nilMap map[string]int
+
+	u.Is(true, s.IsEmpty(nil, "nil"), "nil", t)
+	u.Is(true, s.IsEmpty(nilSlice, "nil slice"), "nil slice", t)
+	u.Is(true, s.IsEmpty(&nilMap, "*nil map"), "*nil map", t)
+	u.Is(true, s.IsEmpty("", "string"), "string", t)
+	u.Is(true, s.IsEmpty([0]int{}, "array"), "array", t)
+	u.Is(true, s.IsEmpty(make(chan int, 1), "chan"), "chan", t)
+	u.Is(true, s.NotEmpty([]int{0}, "slice"), "non-empty slice", t)
+	u.Is(true, s.NotEmpty("x", "string"), "non-empty string", t)
+	m.isOutput("empty out", t)
+
+	u.Is(false, s.IsEmpty([]int{1, 2}, "ids"), "not empty", t)
+	m.isOutput("not empty out", t, "Got non-empty [1 2] for ids.")
+	u.Is(false, s.IsEmpty(strings.Repeat("ab", 30), "long"), "long", t)
+	m.isOutput("long out", t,
+		`Got non-empty "abababababababababababababababababab... for long.`)
+	u.Is(false, s.NotEmpty(nilMap, "map"), "empty map", t)
+	m.isOutput("empty map out", t, "Got empty map[string]int for map.")
+	u.Is(false, s.NotEmpty(nil, "nil"), "nil is empty", t)
+	m.isOutput("nil out", t, "Got empty nil for nil.")
+	u.Is(false, s.IsEmpty(7, "int"), "int has no length", t)
+	m.isOutput("int out", t, "Can't check if empty for int: int has no length")
+}
+
+func TestUnchanged(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+	nums := []int{3, 1, 2}
+	cfg := map[string]bool{"debug": false}
+
+	u.Is(true, s.Unchanged("sum", nums, func() {
+		sum := 0
+		for _, n := range nums {
+			sum += n
+		}
+	}), "read only", t)
+	m.isOutput("read only out", t)
+
+	u.Is(false, s.Unchanged("sort", nums, func() { nums[0], nums[1] = 1, 3 }),
+		"modified slice", t)
+	m.isOutput("modified slice out", t,
+		"Got [1,3,2] not [3,1,2] for sort (changed by run).")
+	u.Is(false, s.Unchanged("cfg", cfg, func() { cfg["debug"] = true }),
+		"modified map", t)
+	m.isOutput("modified map out", t,
+		"\n"+`Got {"debug":true} not {"debug":false} for cfg (changed by run).`)
+
+	type limits struct{ Max int }
+	type handler struct {
+		Fn     func()
+		Limits *limits
+	}
+	h := &handler{Fn: func() {}, Limits: &limits{Max: 5}}
+	u.Is(true, s.Unchanged("h", h, func() { _ = h.Limits.Max }),
+		"func field read only", t)
+	m.isOutput("func field read only out", t)
+	u.Is(false, s.Unchanged("h", h, func() { h.Limits.Max = 6 }),
+		"func field nested change", t)
+	m.isOutput("func field nested change out", t,
+		"Got 6 not 5 at Limits.Max for h (changed by run).")
+}
+
+func TestWithIndent(t *testing.T) {
+	m := new(mock)
+	s := u.New(m).WithIndent("[a] ")
+
+	s.Is(1, 2, "one")
+	m.isOutput("indented out", t, "[a] Got 2 not 1 for one.")
+	s.Is("two\nlines", "one line", "multi-line")
+	m.isOutput("multi-line out", t,
+		"\n[a] Got \"one line\""+
+			"\n[a] not \"two"+
+			"\n[a] ....lines\""+
+			"\n[a] for multi-line.")
+	s.WithIndent("(b) ").Log("note", 2)
+	u.Is(0, m.fails, "Log is not a failure", t)
+	m.isOutput("nested out", t, "[a] (b) note 2")
+}
+
+func TestJsonFields(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+	type user struct {
+		ID      int    `json:"id"`
+		Name    string `json:"name"`
+		Secret  string `json:"-"`
+		Created string `json:"created,omitempty"`
+	}
+
+	u.Is(0, s.JsonFields([]string{"name", "id"}, user{}, "user"), "match", t)
+	m.isOutput("match out", t)
+	u.Is(2, s.JsonFields([]string{"id", "name", "email"}, user{Created: "now"}, "u"),
+		"mismatch", t)
+	m.isOutput("mismatch out", t,
+		`Missing JSON field "email" for u.`,
+		`Got unexpected JSON field "created" for u.`)
+	u.Is(1, s.JsonFields([]string{"id"}, []int{1}, "list"), "array", t)
+	m.isOutput("array out", t, "Got [1] not a JSON object for list.")
+}
+
+type lossy struct {
+	Kept int
+	Lost string `json:"-"`
+}
+
+type badUnmarshal struct{ N int }
+
+func (b *badUnmarshal) UnmarshalJSON([]byte) error {
+	return fmt.Errorf("not supported")
+}
+
+func TestJsonRoundTrips(t *testing.T) {
+	m := new(mock)
+
+	u.Is(true, u.JsonRoundTrips(map[string]int{"a": 1}, "map", m), "map", t)
+	u.Is(true, u.JsonRoundTrips(lossy{Kept: 2}, "kept", m), "kept", t)
+	m.isOutput("round trips out", t)
+
+	u.Is(false, u.JsonRoundTrips(lossy{3, "x"}, "lossy", m), "lossy", t)
+	m.isOutput("lossy out", t,
+		"Got {Kept:3 Lost:} not {Kept:3 Lost:x} for lossy.",
+		`(JSON was {"Kept":3})`)
+	u.Is(false, u.JsonRoundTrips(badUnmarshal{1}, "bad", m), "bad", t)
+	m.isOutput("bad out", t,
+		"Can't convert JSON back to tutl_test.badUnmarshal for bad: "+
+			"not supported",
+		`(JSON was {"N":1})`)
+}
+
+func TestHooks(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+	events := []string{}
+	s.SetHooks(
+		func(desc string) { events = append(events, "before "+desc) },
+		func(desc string, passed bool) {
+			events = append(events, u.S("after ", desc, " ", passed,
+				" output=", len(m.output)))
+		},
+	)
+
+	s.Is(1, 1, "one")
+	s.HasType("int", "x", "type")
+	s.Like("hi", "like", "*bye")
+	u.Is(strings.Join([]string{
+		"before one", "after one true output=0",
+		"before type", "after type false output=1",
+		"before like", "after like false output=3",
+	}, "|"), strings.Join(events, "|"), "hook events", t)
+	m.clear()
+}
+
+func TestRegexpMatches(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+	ids := []string{"a1", "Z99"}
+	bad := []string{"", "1a", "xa1"}
+
+	u.Is(0, s.RegexpMatches(`^[a-zA-Z][0-9]+$`, "id", ids, bad), "good", t)
+	m.isOutput("good out", t)
+	u.Is(2, s.RegexpMatches(`[a-z][0-9]`, "id", ids, bad), "loose", t)
+	m.isOutput("loose out", t,
+		`/[a-z][0-9]/ did not match "Z99" for id.`,
+		`/[a-z][0-9]/ matched unwanted "xa1" for id.`)
+	u.Is(1, s.RegexpMatches(`[a-`, "id", ids, bad), "invalid", t)
+	m.likeOutput("invalid out", t, `^Invalid regexp \(\[a-\) for id: `)
+}
+
+func TestQuoteLoneString(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+
+	s.SetQuoteLoneString(false)
+	u.Is("str", s.S("str"), "lone string not quoted", t)
+	u.Is(`"bin"`, s.S([]byte("bin")), "[]byte still quoted", t)
+	u.Is(`a\tb`, s.S("a\tb"), "still escaped", t)
+	s.Is("yes", "no", "answer")
+	m.isOutput("unquoted out", t, "Got no not yes for answer.")
+	s.SetQuoteLoneString(true)
+	u.Is(`"str"`, s.S("str"), "lone string quoted again", t)
+}
+
+func TestSameSet(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+
+	u.Is(true, s.SameSet([]string{"r", "w", "r"}, []string{"w", "r"}, "perms"),
+		"same set", t)
+	u.Is(true, s.SameSet(nil, []string{}, "none"), "empty sets", t)
+	m.isOutput("same set out", t)
+	u.Is(false, s.SameSet([]string{"r", "w", "w"}, []string{"x", "r", "x"},
+		"perms"), "different sets", t)
+	m.isOutput("different sets out", t,
+		`Missing "w" for perms.`,
+		`Got unexpected "x" for perms.`)
+}
+
+func TestIsDigits(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+
+	u.Is(true, s.IsDigits(3, 0.333, 1.0/3, "third"), "3 digits", t)
+	u.Is(true, s.IsDigits(2, []float32{0.5, 0.33}, []float32{0.5, 1.0 / 3},
+		"float32s"), "2 digits float32", t)
+	m.isOutput("digits out", t)
+	u.Is(false, s.IsDigits(4, 0.333, 1.0/3, "third"), "4 digits", t)
+	m.isOutput("4 digits out", t, "Got 0.3333 not 0.333 for third.")
+	u.Is(false, s.Is(0.333, 1.0/3, "third"), "options not changed", t)
+	m.isOutput("default digits out", t, "Got 0.333333333333 not 0.333 for third.")
+}
+
+func TestIsPermutation(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+
+	u.Is(true, s.IsPermutation([]int{1, 2, 2}, [3]int{2, 1, 2}, "ints"),
+		"permutation", t)
+	m.isOutput("permutation out", t)
+	u.Is(false, s.IsPermutation([]string{"a", "b", "b"},
+		[]string{"b", "x", "b", "b"}, "letters"), "not permutation", t)
+	m.isOutput("not permutation out", t,
+		"Got no permutation for letters:\n"+
+			"    got[0] b <=> want[1] b\n"+
+			"    got[1] x <=> (none)\n"+
+			"    got[2] b <=> want[2] b\n"+
+			"    got[3] b <=> (none)\n"+
+			"    (none)   <=> want[0] a")
+	u.Is(false, s.IsPermutation([]int{1}, 1, "int"), "not a slice", t)
+	m.isOutput("not a slice out", t,
+		"Can't compare elements for int: need a slice or array not int")
+}
+
+type gobbed struct {
+	Kept   int
+	hidden string
+}
+
+func TestGobRoundTrips(t *testing.T) {
+	m := new(mock)
+
+	u.Is(true, u.GobRoundTrips(map[string]int{"a": 1}, "map", m), "map", t)
+	u.Is(true, u.GobRoundTrips(gobbed{Kept: 2}, "kept", m), "kept", t)
+	m.isOutput("round trips out", t)
+
+	u.Is(false, u.GobRoundTrips(gobbed{3, "x"}, "hidden", m), "hidden", t)
+	m.isOutput("hidden out", t,
+		"\nGot {Kept:3 hidden:} not {Kept:3 hidden:x} for hidden.")
+	u.Is(false, u.GobRoundTrips(func() {}, "func", m), "func", t)
+	m.likeOutput("func out", t, "*can't gob-encode func() for func: ")
+}
+
+func TestErrorCollector(t *testing.T) {
+	var ec u.ErrorCollector
+	c := u.New(&ec)
+
+	u.Is(nil, ec.Err(), "no errors yet", t)
+	u.Is(false, ec.Failed(), "not failed yet", t)
+	c.Is(1, 1, "one")
+	c.Is("x", "y", "letter")
+	c.Is("a long enough value to need splitting", "nope", "long")
+	c.Log("just a note")
+	u.Is(true, ec.Failed(), "failed", t)
+	u.Is("Got \"y\" not \"x\" for letter.\n"+
+		"Got \"nope\" not \"a long enough value to need splitting\" "+
+		"for long.", ec.Err(), "joined errors", t)
+	u.Is(1, len(ec.Logs), "logs kept", t)
+}
+
+func TestWithEnv(t *testing.T) {
+	os.Setenv("TUTL_KEPT", "old")
+	os.Unsetenv("TUTL_NEW")
+	defer os.Unsetenv("TUTL_KEPT")
+
+	u.WithEnv(map[string]string{"TUTL_KEPT": "new", "TUTL_NEW": "x"}, func() {
+		u.Is("new", os.Getenv("TUTL_KEPT"), "changed var", t)
+		u.Is("x", os.Getenv("TUTL_NEW"), "new var", t)
+	})
+	u.Is("old", os.Getenv("TUTL_KEPT"), "var restored", t)
+	_, isSet := os.LookupEnv("TUTL_NEW")
+	u.Is(false, isSet, "new var unset", t)
+
+	u.Is("oops", u.GetPanic(func() {
+		u.WithEnv(map[string]string{"TUTL_NEW": "y"}, func() { panic("oops") })
+	}), "panic passed through", t)
+	_, isSet = os.LookupEnv("TUTL_NEW")
+	u.Is(false, isSet, "unset after panic", t)
+}
+
+func TestIsStable(t *testing.T) {
+	m := new(mock)
+	calls := 0
+	counter := func() int { calls++; return calls / 3 }
+
+	u.Is(true, u.IsStable("const", m, 10, func() string { return "x" }),
+		"stable", t)
+	u.Is(true, u.IsStable("counter", m, 2, counter), "stable enough", t)
+	m.isOutput("stable out", t)
+	calls = 0
+	u.Is(false, u.IsStable("counter", m, 10, counter), "unstable", t)
+	m.isOutput("unstable out", t, "Got 1 from call 3 not 0 for counter.")
+	calls = 0
+	u.Is(false, u.IsStable("none", m, 0, counter), "no calls", t)
+	u.Is(0, calls, "not called", t)
+	m.isOutput("no calls out", t,
+		"IsStable() needs a positive call count in test code, not 0, for none.")
+
+	s := u.New(m)
+	s.SetDescTransform(strings.ToUpper)
+	u.Is(false, u.IsStable("counter", s, 10, counter), "options", t)
+	m.isOutput("options out", t, "Got 1 from call 3 not 0 for COUNTER.")
+}
+
+func TestSub(t *testing.T) {
+	p := u.New(t)
+	p.SetThousandsSep(',')
+	p.Sub("inherits", t, func(s u.TUTL) {
+		s.Is("1,000", s.S(1000), "inherited separator")
+		s.SetThousandsSep('_')
+		s.Is("1_000", s.S(1000), "changed separator")
+	})
+	p.Sub("isolated", t, func(s u.TUTL) {
+		s.Is("1,000", s.S(1000), "change did not leak")
+	})
+	p.Is("1,000", p.S(1000), "parent unchanged")
+}
+
+func TestThat(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+	even := func(v interface{}) bool { return 0 == v.(int)%2 }
+
+	u.Is(true, s.That(4, "even", even), "4 is even", t)
+	u.Is(true, u.ThatT("abc", "short", m, func(s string) bool {
+		return len(s) < 5
+	}), "ThatT passes", t)
+	m.isOutput("That passes out", t)
+	u.Is(false, s.That(3, "even", even), "3 is odd", t)
+	m.isOutput("That fails out", t, "Got 3 which fails predicate for even.")
+	u.Is(false, u.ThatT("abcdef", "short", m, func(s string) bool {
+		return len(s) < 5
+	}), "ThatT fails", t)
+	m.isOutput("ThatT fails out", t,
+		`Got "abcdef" which fails predicate for short.`)
+}
+
+func TestErrorFormat(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+
+	u.Is(true, s.ErrorFormat(fmt.Errorf("open x: not found"), "ok"), "ok", t)
+	u.Is(true, s.ErrorFormat(io.EOF, "EOF"), "acronym", t)
+	m.isOutput("well formatted out", t)
+
+	u.Is(false, s.ErrorFormat(fmt.Errorf("Failed."), "bad"), "bad", t)
+	m.isOutput("bad out", t,
+		`Error "Failed." starts with uppercase for bad.`,
+		`Error "Failed." ends with '.' for bad.`)
+	u.Is(false, s.ErrorFormat(fmt.Errorf("oops\n"), "lf"), "newline", t)
+	m.isOutput("newline out", t, "Error \"oops\n....\" ends with '\\n' for lf.")
+	u.Is(false, s.ErrorFormat(nil, "nil"), "nil", t)
+	m.isOutput("nil out", t, "Got nil error for nil.")
+	s.SetStrictErrorCase(true)
+	u.Is(false, s.ErrorFormat(io.EOF, "EOF"), "strict", t)
+	m.isOutput("strict out", t, `Error "EOF" starts with uppercase for EOF.`)
+}
+
+func TestCanceled(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	u.Is(true, s.NotCanceled(ctx, 0, "live"), "live now", t)
+	u.Is(true, s.NotCanceled(ctx, time.Millisecond, "live"), "stays live", t)
+	u.Is(false, s.IsCanceled(ctx, time.Millisecond, "live"), "not canceled", t)
+	m.isOutput("not canceled out", t,
+		"Got context not canceled after 1ms for live.")
+
+	errDone := fmt.Errorf("done")
+	go func() {
+		time.Sleep(time.Millisecond)
+		cancel(fmt.Errorf("wrapped: %w", errDone))
+	}()
+	u.Is(true, s.IsCanceled(ctx, time.Minute, "dead"), "canceled", t)
+	m.isOutput("canceled out", t)
+	u.Is(true, s.IsCanceled(ctx, 0, "dead", io.EOF, errDone), "cause", t)
+	m.isOutput("cause out", t)
+	u.Is(false, s.IsCanceled(ctx, 0, "dead", io.EOF, context.Canceled),
+		"wrong cause", t)
+	m.isOutput("wrong cause out", t,
+		"Got context canceled (wrapped: done) not (EOF or context canceled)"+
+			" for dead.")
+	u.Is(false, s.NotCanceled(ctx, time.Minute, "dead"), "not live", t)
+	m.isOutput("not live out", t,
+		"Got context canceled (wrapped: done) for dead.")
+	s.SetVerbose(true)
+	u.Is(true, s.IsCanceled(ctx, 0, "dead"), "verbose canceled", t)
+	m.isOutput("verbose out", t,
+		"Got context canceled (wrapped: done) for dead.")
+}
+
+func TestMapDiff(t *testing.T) {
+	want := u.Map{"name": "app", "debug": false,
+		"db": map[string]interface{}{"host": "h", "port": 5432}}
+	u.Is("", u.MapDiff(want, want), "same", t)
+
+	got := u.Map{"name": "app", "extra": 1,
+		"db": u.Map{"host": "h", "port": 5433}}
+	u.Is(
+		"key      want       got\n"+
+			"db.port  5432       5433\n"+
+			"debug    false      (missing)\n"+
+			"extra    (missing)  1\n",
+		u.MapDiff(want, got), "diff", t)
+
+	u.Is(
+		"key   want     got\n"+
+			"café  \"naïve\"  \"né\"\n",
+		u.MapDiff(u.Map{"café": "naïve"}, u.Map{"café": "né"}), "runes", t)
+}
+
+func TestMarshalsTo(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+
+	type item struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+	u.Is(true, s.MarshalsTo(`{ "name": "x", "id": 7 }`, item{7, "x"}, "ok"),
+		"same", t)
+	m.isOutput("same out", t)
+
+	u.Is(false, s.MarshalsTo(`{"id":7,"name":"y"}`, item{7, "x"}, "item"),
+		"diff", t)
+	m.isOutput("diff out", t,
+		"\n"+`Got {"id":7,"name":"x"} not {"id":7,"name":"y"} for item.`,
+		"\nkey   want  got\nname  \"y\"   \"x\"\n")
+	u.Is(false, s.MarshalsTo(`[1,`, 1, "bad"), "bad want", t)
+	m.likeOutput("bad want out", t, "Invalid 'want' JSON for bad: ")
+	u.Is(false, s.MarshalsTo(`1`, func() {}, "func"), "bad got", t)
+	m.likeOutput("bad got out", t, "Can't marshal func to JSON: ")
+}
+
+func TestNoLeadingNewline(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+
+	s.SetNoLeadingNewline(true)
+	s.Is("longish stuff", "longer stuff", "were stuff longer or longish")
+	m.isOutput("middle out", t,
+		`Got "longer stuff" not "longish stuff" for `+
+			`were stuff longer or longish.`)
+	s.Is("longish stuff", "longer stuffy", "were stuff longer or longish")
+	m.isOutput("long out", t,
+		"\nGot \"longer stuffy\""+
+			"\nnot \"longish stuff\""+
+			"\nfor were stuff longer or longish.")
+}
+
+func TestMeasurePath(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+
+	// Fails Is(1, 2) and returns the length of the path shown for that.
+	isTwo := func() int {
+		_, file, line, _ := runtime.Caller(0)
+		s.Is(1, 2, "x") // Must be on the line after runtime.Caller(0).
+		return 4 + len(filepath.Base(file)) + len(fmt.Sprint(line+1)) + 3
+	}
+	s.SetLineWidth(40)
+	s.SetPathLength(40 - len("Got 2 not 1 for x."))
+	plen := isTwo()
+	m.isOutput("static", t, "Got 2 not 1 for x.")
+	s.SetMeasurePath(true)
+	s.SetLineWidth(len("Got 2 not 1 for x.") + plen - 1)
+	isTwo()
+	m.isOutput("measured", t, "\nGot 2 not 1 for x.")
+	s.SetLineWidth(len("Got 2 not 1 for x.") + plen)
+	isTwo()
+	m.isOutput("measured fits", t, "Got 2 not 1 for x.")
+}
+
+func TestStructDiff(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+
+	type host struct {
+		Name string
+		Port int
+	}
+	type config struct {
+		Hosts []host
+		Tags  map[string]string
+		Main  *host
+		note  string
+	}
+	want := config{
+		Hosts: []host{{"a", 1}, {"b", 2}},
+		Tags:  map[string]string{"env": "prod", "team": "x"},
+		Main:  &host{"a", 1},
+	}
+	u.Is(0, s.StructDiff(want, want, "same"), "same", t)
+	m.isOutput("same out", t, "Skipped unexported field note for same.")
+
+	got := config{
+		Hosts: []host{{"a", 1}, {"c", 3}, {"d", 4}},
+		Tags:  map[string]string{"env": "dev", "owner": "y"},
+	}
+	u.Is(7, s.StructDiff(want, got, "cfg"), "diffs", t)
+	m.isOutput("diffs out", t,
+		"Got length 3 not 2 at Hosts for cfg.",
+		`Got "c" not "b" at Hosts.1.Name for cfg.`,
+		"Got 3 not 2 at Hosts.1.Port for cfg.",
+		`Got "dev" not "prod" at Tags.env for cfg.`,
+		`Got "y" not (missing) at Tags.owner for cfg.`,
+		`Got (missing) not "x" at Tags.team for cfg.`,
+		"Got <nil> not &{a 1} at Main for cfg.",
+		"Skipped unexported field note for cfg.")
+}
+
+func TestMaxFailures(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+
+	s.SetMaxFailures(2)
+	u.Is(4, s.Like("abc", "like", "*x", "*y", "*z", "d"), "like", t)
+	m.isOutput("like out", t,
+		"No <x>...",
+		"and No <y>...",
+		"...and 2 more failures",
+		"In <abc> for like.")
+	u.Is(4, s.Like("abc", "bad", "*x", "*y", "*z", ""), "bad match", t)
+	m.isOutput("bad match out", t,
+		"No <x>...",
+		"and No <y>...",
+		"...and 1 more failures",
+		`Match strings passed to Like() must not be empty nor "!"`)
+	u.Is(false, s.SameSet([]string{"a", "b"}, []string{"c"}, "set"), "set", t)
+	m.isOutput("set out", t,
+		`Missing "a" for set.`,
+		`Missing "b" for set.`,
+		"...and 1 more failures")
+	u.Is(2, s.RegexpMatches("^a", "re", nil, []string{"ab", "ac"}), "re", t)
+	m.isOutput("re out", t,
+		`/^a/ matched unwanted "ab" for re.`,
+		`/^a/ matched unwanted "ac" for re.`)
+
+	s.SetMaxFailures(0)
+	u.Is(3, s.Like("abc", "all", "*x", "*y", "*z"), "unlimited", t)
+	m.isOutput("unlimited out", t,
+		"No <x>...", "and No <y>...", "and No <z>...", "In <abc> for all.")
+}
+
+func TestParsesAs(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+
+	u.Is(true, s.ParsesAs(uint8(200), "200", "uint8"), "uint8", t)
+	u.Is(true, s.ParsesAs(-12, "-12", "int"), "int", t)
+	u.Is(true, s.ParsesAs(0.5, "0.5", "float"), "float", t)
+	m.isOutput("ok out", t)
+
+	u.Is(false, s.ParsesAs(uint8(200), "300", "big"), "range", t)
+	m.isOutput("range out", t, `Can't parse "300" as uint8 for big:`+
+		` strconv.ParseUint: parsing "300": value out of range`)
+	u.Is(false, s.ParsesAs(7, "x", "x"), "syntax", t)
+	m.likeOutput("syntax out", t, `Can't parse "x" as int for x: `)
+	u.Is(false, s.ParsesAs(7, "8", "eight"), "value", t)
+	m.isOutput("value out", t, "Got 8 not 7 for eight.")
+	u.Is(false, s.ParsesAs(float32(0.1), "0.1000001", "f32"), "float32", t)
+	m.isOutput("float32 out", t, "Got 0.1000001 not 0.1 for f32.")
+	u.Is(false, s.ParsesAs(0.3, "0.30000000000000004", "f64"), "float64", t)
+	m.isOutput("float64 out", t, "Got 0.30000000000000004 not 0.3 for f64.")
+	u.Is(true, s.ParsesAs(math.NaN(), "NaN", "nan"), "nan", t)
+	u.Is(true, s.ParsesAs(float32(1e10), "1e10", "exp"), "exponent", t)
+	m.isOutput("exact out", t)
+	u.Is(false, s.ParsesAs("7", "7", "str"), "non-number", t)
+	m.isOutput("non-number out", t,
+		"ParsesAs() needs a number not string in test code for str.")
+}
+
+func TestTrimLikeInput(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+
+	u.Is(1, s.Like("done\n", "log", "done$"), "untrimmed", t)
+	m.isOutput("untrimmed out", t, "Not like /done$/...", "In <done\n> for log.")
+	s.SetTrimLikeInput(true)
+	u.Is(0, s.Like("  done\n", "log", "^done$", "!^ "), "trimmed", t)
+	u.Is(1, s.Like(" \n", "blank", "x"), "blank", t)
+	m.isOutput("trimmed out", t, "No string to check what it is Like(); got blank.")
+}
+
+func TestCallRecorder(t *testing.T) {
+	record, calls := u.CallRecorder[string]()
+	u.Is(0, len(calls()), "none yet", t)
+	for _, s := range []string{"a", "b", "c"} {
+		record(s)
+	}
+	got := calls()
+	u.Is("[a b c]", got, "recorded", t)
+	got[0] = "x"
+	u.Is("[a b c]", calls(), "copy", t)
+}
+
+func TestCalledTimes(t *testing.T) {
+	m := new(mock)
+
+	record, calls := u.CallRecorder[int]()
+	u.Is(true, u.CalledTimes(0, calls, "none", m), "none", t)
+	record(5)
+	record(7)
+	u.Is(true, u.CalledTimes(2, calls, "two", m), "two", t)
+	m.isOutput("pass out", t)
+	u.Is(false, u.CalledTimes(1, calls, "one", m), "one", t)
+	m.isOutput("fail out", t,
+		"Got 2 calls not 1 for one.", "    call 1: 5", "    call 2: 7")
+}
+
+type status int
+
+func TestRegisterEnum(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+
+	u.Is("1", s.S(status(1)), "unregistered", t)
+	u.RegisterEnum(status(0), map[int]string{0: "StatusNew", 1: "StatusActive"})
+	defer u.RegisterEnum(status(0), nil)
+	u.Is("StatusActive(1)", s.S(status(1)), "registered", t)
+	u.Is("status(7)", s.S(status(7)), "unnamed", t)
+	u.Is("1", s.V(status(1)), "V unchanged", t)
+
+	s.Is(status(1), status(0), "state")
+	m.isOutput("names out", t, "Got StatusNew(0) not StatusActive(1) for state.")
+	u.Is(true, s.Is(1, status(1), "numeric"), "numeric", t)
+	u.Is(true, nil != u.GetPanic(func() { u.RegisterEnum("x", nil) }),
+		"non-integer panics", t)
+}
+
+func TestIsValidEnum(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+
+	u.Is(true, s.IsValidEnum(status(1), "listed", status(0), status(1)),
+		"listed", t)
+	m.isOutput("listed out", t)
+	u.Is(false, s.IsValidEnum(status(7), "bad", status(0), status(1)),
+		"bad", t)
+	m.isOutput("bad out", t, "Got 7 which is not a valid status (0, 1) for bad.")
+
+	u.RegisterEnum(status(0), map[int]string{0: "StatusNew", 1: "StatusActive"})
+	defer u.RegisterEnum(status(0), nil)
+	u.Is(true, s.IsValidEnum(status(0), "registered"), "registered", t)
+	u.Is(false, s.IsValidEnum(status(7), "state"), "unregistered value", t)
+	m.isOutput("registered out", t, "Got status(7) which is not a valid"+
+		" status (StatusNew(0), StatusActive(1)) for state.")
+}
+
+func TestJsonArrayUnordered(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+
+	u.Is(true, s.JsonArrayUnordered(`[{"b":2,"a":1}, 3, 3]`,
+		[]interface{}{3, map[string]int{"a": 1, "b": 2}, 3}, "ok"), "ok", t)
+	m.isOutput("ok out", t)
+
+	u.Is(false, s.JsonArrayUnordered(`[1, 2, 2]`, `[2, 3, 1]`, "nums"),
+		"diff", t)
+	m.isOutput("diff out", t, "Missing 2 for nums.", "Got unexpected 3 for nums.")
+	u.Is(false, s.JsonArrayUnordered(`[]`, `{}`, "obj"), "not array", t)
+	m.isOutput("not array out", t, "Got JSON object not array for 'got' for obj.")
+	u.Is(false, s.JsonArrayUnordered(`[`, `[]`, "bad"), "invalid", t)
+	m.likeOutput("invalid out", t, "Invalid JSON for 'want' for bad: ")
+}
+
+func TestIsJsonNear(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+
+	want := `{"name": "a", "pts": [{"x": 1.5, "y": 2}], "ok": true}`
+	got := map[string]interface{}{"name": "a", "ok": true,
+		"pts": []map[string]float64{{"x": 1.50004, "y": 1.99999}}}
+	u.Is(0, s.IsJsonNear(want, got, 1e-4, "near"), "near", t)
+	m.isOutput("near out", t)
+
+	got = map[string]interface{}{"name": "b", "extra": nil,
+		"pts": []map[string]interface{}{{"x": 1.6, "y": "2"}, {}}}
+	u.Is(6, s.IsJsonNear(want, got, 0.05, "pts"), "far", t)
+	m.isOutput("far out", t,
+		"Got unexpected extra for pts.",
+		`Got "b" not "a" at name for pts.`,
+		"No ok found for pts.",
+		"Got 1.6 not 1.5 (+/-0.05) at pts.0.x for pts.",
+		"Got string not number at pts.0.y for pts.",
+		"Got unexpected pts.1 for pts.")
+	u.Is(1, s.IsJsonNear(`1`, `[]`, 1, "top"), "top", t)
+	m.isOutput("top out", t, "Got array not number at top level for top.")
+	u.Is(1, s.IsJsonNear(`1`, `{`, 1, "bad"), "bad", t)
+	m.likeOutput("bad out", t, "Invalid JSON for 'got' for bad: ")
+}
+
+func TestAgreeOn(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+
+	before := `{"id": 7, "name": "x", "tags": ["a", "b"], "seen": 1,
+		"owner": {"name": "bo", "admin": false}}`
+	after := map[string]interface{}{"id": 7.0, "name": "y", "seen": 2,
+		"tags":  []string{"a"},
+		"owner": map[string]interface{}{"admin": false, "name": "bo"}}
+	u.Is(0, s.AgreeOn(before, after, []string{"id", "owner", "tags.0"},
+		"stable"), "agree", t)
+	m.isOutput("agree out", t)
+
+	u.Is(4, s.AgreeOn(before, after,
+		[]string{"name", "tags.1", "gone", "owner.admin", "seen"}, "user"),
+		"disagree", t)
+	m.isOutput("disagree out", t,
+		`Got "y" not "x" at name for user.`,
+		"No tags.1 found in 'b' for user.",
+		"No gone found in 'a' nor 'b' for user.",
+		"Got 2 not 1 at seen for user.")
+	u.Is(1, s.AgreeOn(`{`, after, []string{"id"}, "bad"), "bad", t)
+	m.likeOutput("bad out", t, "Invalid JSON for 'a' for bad: ")
+}
+
+func TestSnapshotValue(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+	dir := t.TempDir()
+	s.SetSnapshotDir(filepath.Join(dir, "snaps"))
+
+	type item struct {
+		SKU string
+		Qty int
+	}
+	order := map[string]interface{}{"id": 7, "items": []item{{"a", 2}}}
+	u.Is(false, s.SnapshotValue("order", order), "missing", t)
+	m.likeOutput("missing out", t, "^Can't read snapshot order: ")
+
+	u.WithEnv(map[string]string{"TUTL_UPDATE_SNAPSHOTS": "1"}, func() {
+		u.Is(true, s.SnapshotValue("order", order), "update", t)
+	})
+	m.likeOutput("update out", t, `^Updated snapshot order \(.*order\.json\)\.`)
+	u.FileIs("{\n    \"id\": 7,\n    \"items\": [\n        {\n"+
+		"            \"SKU\": \"a\",\n            \"Qty\": 2\n"+
+		"        }\n    ]\n}\n", filepath.Join(dir, "snaps", "order.json"), t)
+
+	u.Is(true, s.SnapshotValue("order", order), "same", t)
+	m.isOutput("same out", t)
+	order["items"] = []item{{"a", 3}}
+	u.Is(false, s.SnapshotValue("order", order), "changed", t)
+	m.isOutput("changed out", t,
+		"Got 3 not 2 at items.0.Qty for snapshot order.")
+
+	flag.Set("update", "true")
+	u.Is(true, s.SnapshotValue("order", order), "update flag", t)
+	flag.Set("update", "false")
+	m.likeOutput("update flag out", t, `^Updated snapshot order \(`)
+	u.Is(true, s.SnapshotValue("order", order), "updated", t)
+	m.isOutput("updated out", t)
+}
+
+func TestErrorChain(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+
+	base := fmt.Errorf("base")
+	other := fmt.Errorf("other")
+	err := fmt.Errorf("save: %w", fmt.Errorf("open: %w", base))
+	u.Is(0, s.ErrorChain(err, "ok", base), "found", t)
+	m.isOutput("found out", t)
+
+	u.Is(1, s.ErrorChain(err, "save", base, other), "missing", t)
+	m.isOutput("missing out", t,
+		`Missing "other" in error chain for save.`,
+		`    chain: "save: open: base" (*fmt.wrapError)`,
+		`    chain: "open: base" (*fmt.wrapError)`,
+		`    chain: "base" (*errors.errorString)`)
+	u.Is(2, s.ErrorChain(nil, "nil", base, other), "nil", t)
+	m.isOutput("nil out", t, "Got nil error not a chain for nil.")
+}
+
+type userID string
+
+func TestRegisterEqual(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+
+	u.Is(false, s.Is(userID("Bob"), userID("bob"), "id"), "unregistered", t)
+	m.isOutput("unregistered out", t, `Got bob not Bob for id.`)
+	u.RegisterEqual(userID(""), func(a, b interface{}) bool {
+		return strings.EqualFold(string(a.(userID)), string(b.(userID)))
+	})
+	defer u.RegisterEqual(userID(""), nil)
+	u.Is(true, s.Is(userID("Bob"), userID("bob"), "id"), "registered", t)
+	u.Is(false, s.IsNot(userID("Bob"), userID("BOB"), "id"), "IsNot", t)
+	m.isOutput("IsNot out", t, "Got unwanted BOB for id.")
+	u.Is(false, s.Is("Bob", userID("bob"), "mixed"), "mixed types", t)
+	m.isOutput("mixed out", t, `Got bob not "Bob" for mixed.`)
+}
+
+func TestFileIs(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "out.txt")
+	if err := os.WriteFile(path, []byte("hi"), 0644); nil != err {
+		t.Fatal(err)
+	}
+	u.Is(true, s.FileIs("hi", path), "string", t)
+	u.Is(true, s.FileIs([]byte("hi"), path), "bytes", t)
+	m.isOutput("same out", t)
+
+	s.SetLineWidth(1000)
+	u.Is(false, s.FileIs("bye", path), "diff", t)
+	m.isOutput("diff out", t, `Got "hi" not "bye" for file `+path+".")
+	u.Is(false, s.FileIs("", filepath.Join(dir, "none")), "missing", t)
+	m.likeOutput("missing out", t, "Can't read file .*none: .*no such file")
+}
+
+func TestDirHas(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+
+	dir := t.TempDir()
+	for _, f := range []string{"a.txt", "sub/b.txt", "sub/deep/c.txt"} {
+		path := filepath.Join(dir, f)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); nil != err {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0644); nil != err {
+			t.Fatal(err)
+		}
+	}
+	u.Is(0, s.DirHas(dir, "sub/", "a.txt"), "top", t)
+	m.isOutput("top out", t)
+	s.SetLineWidth(1000)
+	u.Is(2, s.DirHas(dir, "a.txt", "b.txt"), "top diff", t)
+	m.isOutput("top diff out", t,
+		`Missing "b.txt" for dir `+dir+".",
+		`Got unexpected "sub/" for dir `+dir+".")
+
+	s.SetRecurseDirs(true)
+	u.Is(0, s.DirHas(dir, "sub/deep/c.txt", "a.txt", "sub/b.txt"), "deep", t)
+	m.isOutput("deep out", t)
+	u.Is(1, s.DirHas(filepath.Join(dir, "none")), "no dir", t)
+	m.likeOutput("no dir out", t, "Can't list dir .*none: ")
+}
+
+func TestWithinPercent(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+
+	u.Is(true, s.WithinPercent(100, 110, 10, "edge"), "edge", t)
+	u.Is(true, s.WithinPercent(-100, -95, 10, "negative"), "negative", t)
+	u.Is(true, s.WithinPercent(0, 0, 1, "zero"), "zero", t)
+	m.isOutput("pass out", t)
+
+	u.Is(false, s.WithinPercent(100, 118, 10, "ops"), "over", t)
+	m.isOutput("over out", t,
+		"Got 118 not within 10% of 100 for ops (deviated 18%, allowed 10%).")
+	u.Is(false, s.WithinPercent(0, 1, 50, "zero"), "zero base", t)
+	m.isOutput("zero base out", t,
+		"Got 1 not within 50% of 0 for zero (deviated +Inf%, allowed 50%).")
+	u.Is(false, s.WithinPercent(1, math.NaN(), 50, "nan"), "NaN", t)
+	m.isOutput("NaN out", t,
+		"Got NaN not within 50% of 1 for nan (deviated NaN%, allowed 50%).")
+}
+
+func TestIsMultipleOf(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+
+	u.Is(true, s.IsMultipleOf(4096, 8192, "aligned"), "aligned", t)
+	u.Is(true, s.IsMultipleOf(-3, 9, "negative"), "negative", t)
+	u.Is(true, s.IsMultipleOf(7, 0, "zero"), "zero", t)
+	m.isOutput("pass out", t)
+	u.Is(false, s.IsMultipleOf(4096, 5000, "size"), "not", t)
+	m.isOutput("not out", t, "Got 5000 not a multiple of 4096 for size"+
+		" (remainder 904).")
+	u.Is(false, s.IsMultipleOf(0, 5, "zero factor"), "zero factor", t)
+	m.isOutput("zero factor out", t,
+		"Called IsMultipleOf() with a factor of 0 in test code for zero factor.")
+
+	u.Is(true, s.IsPowerOfTwo(1, "one"), "one", t)
+	u.Is(true, s.IsPowerOfTwo(1<<62, "big"), "big", t)
+	m.isOutput("power out", t)
+	u.Is(false, s.IsPowerOfTwo(100, "buckets"), "100", t)
+	m.isOutput("100 out", t,
+		"Got 100 not a power of two for buckets (between 64 and 128).")
+	u.Is(false, s.IsPowerOfTwo(0, "zero"), "0", t)
+	m.isOutput("0 out", t, "Got 0 not a power of two for zero.")
+	u.Is(false, s.IsPowerOfTwo(-4, "neg"), "-4", t)
+	m.isOutput("-4 out", t, "Got -4 not a power of two for neg.")
+}
+
+func TestInSet(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+
+	u.Is(true, s.InSet("open", "state", "open", "closed"), "in", t)
+	u.Is(true, s.InSet(2, "num", "1", "2"), "number", t)
+	m.isOutput("in out", t)
+	u.Is(false, s.InSet("done", "state", "open", "closed"), "out", t)
+	m.isOutput("out out", t,
+		`Got "done" not one of ("open", "closed") for state.`)
+}
+
+func TestDeepCopy(t *testing.T) {
+	type fixture struct {
+		Names []string
+		Tags  map[string]int
+	}
+	orig := fixture{[]string{"a"}, map[string]int{"x": 1}}
+	c := u.DeepCopy(orig)
+	c.Names[0] = "b"
+	c.Tags["x"] = 2
+	u.Is("{[a] map[x:1]}", orig, "copy", t)
+
+	type private struct {
+		x int
+		Y int
+	}
+	u.Is("{x:5 Y:6}", fmt.Sprintf("%+v", u.DeepCopy(private{x: 5, Y: 6})),
+		"unexported kept", t)
+
+	var ints interface{} = []int{1, 2}
+	ic := u.DeepCopy(ints)
+	u.Is("[]int", fmt.Sprintf("%T", ic), "interface type kept", t)
+	ic.([]int)[0] = 3
+	u.Is("[1 2]", ints, "interface copied", t)
+
+	type withFunc struct {
+		Fn    func()
+		Names []string
+		Next  *withFunc
+	}
+	wf := &withFunc{Names: []string{"a"}, Next: &withFunc{Names: []string{"n"}}}
+	wc := u.DeepCopy(wf)
+	wc.Names[0] = "b"
+	wc.Next.Names[0] = "m"
+	u.Is("a n", wf.Names[0]+" "+wf.Next.Names[0], "reflect copy", t)
+	u.Is("b m", wc.Names[0]+" "+wc.Next.Names[0], "reflect copied", t)
+
+	type ring struct {
+		Fn   func()
+		Name string
+		Next *ring
+		Peer map[string]*ring
+	}
+	r := &ring{Name: "a"}
+	r.Next = &ring{Name: "b", Next: r}
+	r.Peer = map[string]*ring{"self": r}
+	rc := u.DeepCopy(r)
+	u.Is(true, rc != r && rc.Next != r.Next, "cycle copied", t)
+	u.Is(true, rc == rc.Next.Next && rc == rc.Peer["self"], "cycle kept", t)
+	rc.Next.Name = "c"
+	u.Is("b", r.Next.Name, "cycle original", t)
+}
+
+func TestElementTypes(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+
+	toks := []interface{}{"x", '=', 1.5, nil}
+	u.Is(0, s.ElementTypes(toks, "tokens", "string", "int32", "float64", "nil"),
+		"match", t)
+	m.isOutput("match out", t)
+	u.Is(2, s.ElementTypes(toks, "tokens", "string", "int32", "int"), "wrong", t)
+	m.isOutput("wrong out", t,
+		"Got 4 elements not 3 for tokens.",
+		"Got float64 not int at index 2 for tokens.")
+	u.Is(1, s.ElementTypes(nil, "empty", "string"), "short", t)
+	m.isOutput("short out", t, "Got 0 elements not 1 for empty.")
+}
+
+func TestNoDuplicates(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+
+	u.Is(0, s.NoDuplicates([]int{1, 2, 3}, "unique"), "unique", t)
+	m.isOutput("unique out", t)
+	u.Is(3, s.NoDuplicates([]string{"a", "b", "a", "b", "a"}, "ids"),
+		"dups", t)
+	m.isOutput("dups out", t,
+		`Got duplicate "a" at indices 0, 2, 4 for ids.`,
+		`Got duplicate "b" at indices 1, 3 for ids.`)
+	u.Is(1, s.NoDuplicates(7, "int"), "not slice", t)
+	m.isOutput("not slice out", t,
+		"Can't check elements for int: need a slice or array not int")
+}
+
+type node struct {
+	Name string
+	Next *node
+}
+
+func TestMaxDepth(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+
+	loop := &node{Name: "a"}
+	loop.Next = loop
+	other := &node{Name: "a"}
+	other.Next = other
+	s.SetMaxDepth(4)
+	u.Is(0, s.StructDiff(loop.Next, other, "loop"), "loop", t)
+	m.isOutput("loop out", t, "Cycle detected at Next for loop.")
+	long := &node{Name: "a"}
+	long.Next = &node{Name: "a", Next: long}
+	u.Is(0, s.StructDiff(loop, long, "long"), "long", t)
+	m.isOutput("long out", t,
+		"Max depth (4) exceeded at Next.Next for long.")
+	deep := &node{"a", &node{"b", &node{"c", nil}}}
+	s.SetMaxDepth(-1)
+	u.Is(0, s.StructDiff(deep, deep, "deep"), "unlimited", t)
+	m.isOutput("unlimited out", t)
+}
+
+func TestTimeFormatIs(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+
+	when := time.Date(2024, 3, 1, 15, 4, 5, 0, time.UTC)
+	u.Is(true, s.TimeFormatIs("2024-03-01", when, time.DateOnly, "date"),
+		"date", t)
+	m.isOutput("date out", t)
+	u.Is(false, s.TimeFormatIs("3:04AM", when, time.Kitchen, "kitchen"),
+		"kitchen", t)
+	m.isOutput("kitchen out", t, `Got "3:04PM" not "3:04AM" for kitchen.`)
+}
+
+func TestHasInt(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+
+	body := `{"id": 9007199254740993, "n": 2, "f": 1.5, "s": "x", "a": [7]}`
+	u.Is(0, s.HasInt(body, "ok", "id", int64(9007199254740993), "n", uint8(2),
+		"a.0", 7), "ok", t)
+	m.isOutput("ok out", t)
+
+	u.Is(4, s.HasInt(body, "body", "id", int64(9007199254740992), "f", 1,
+		"s", 1, "none", 1), "fails", t)
+	m.isOutput("fails out", t,
+		"Got 9007199254740993 not 9007199254740992 at id for body.",
+		"Got 1.5 at f for body: not an integer (precision would be lost)",
+		"Got string not number at s for body.",
+		"No none found for body.")
+	u.Is(1, s.HasInt(body, "odd", "id"), "odd", t)
+	m.isOutput("odd out", t,
+		"HasInt() needs key/value pairs in test code for odd.")
+	u.Is(1, s.HasInt(body, "key", 1, 2, "n", 2), "key", t)
+	m.isOutput("key out", t,
+		"HasInt() needs a string key not int in test code for key.")
+}
+
+func TestIsZero(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+
+	type pair struct{ A, B int }
+	u.Is(true, s.IsZero(nil, "nil"), "nil", t)
+	u.Is(true, s.IsZero(pair{}, "struct"), "struct", t)
+	u.Is(true, s.IsZero("", "string"), "string", t)
+	u.Is(true, s.NotZero(pair{0, 1}, "set"), "set", t)
+	m.isOutput("pass out", t)
+
+	u.Is(false, s.IsZero(pair{0, 1}, "pair"), "non-zero", t)
+	m.isOutput("non-zero out", t, "Got non-zero {0 1} for pair.")
+	u.Is(false, s.NotZero(pair{}, "pair"), "zero", t)
+	m.isOutput("zero out", t, "Got zero tutl_test.pair for pair.")
+	u.Is(false, s.NotZero(nil, "nil"), "nil zero", t)
+	m.isOutput("nil zero out", t, "Got zero nil for nil.")
+}
+
+func TestLikeLabeled(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+
+	u.Is(2, s.LikeLabeled("load: bad file", "err", map[string]string{
+		"error prefix": "^load:",
+		"names file":   "*config.yaml",
+		"no panic":     "!*panic",
+		"reason":       "bad value",
+	}), "labeled", t)
+	m.isOutput("labeled out", t,
+		"No <config.yaml> (label: 'names file')...",
+		"and Not like /bad value/ (label: 'reason')...",
+		"In <load: bad file> for err.")
+}
+
+func TestScanLike(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+
+	log := "starting\r\nListening on :80\nrequest ok\nrequest ok\n"
+	u.Is(0, s.ScanLike(strings.NewReader(log), "log",
+		"*listening ON", "^request ok$", "!*panic"), "pass", t)
+	m.isOutput("pass out", t)
+
+	log = "start\npanic: boom\nmore\npanic again"
+	u.Is(3, s.ScanLike(strings.NewReader(log), "log",
+		"*Listening", "!*Panic", "!^more$", "^start$"), "fail", t)
+	m.isOutput("fail out", t,
+		"Found unwanted <Panic> on line 2: <panic: boom>",
+		"Found unwanted /^more$/ on line 3: <more>",
+		"No <Listening> in any line...",
+		"In 4 lines for log.")
+	u.Is(1, s.ScanLike(strings.NewReader(""), "empty", "x"), "empty", t)
+	m.isOutput("empty out", t, "No /x/ in any line...", "In 0 lines for empty.")
+	u.Is(1, s.ScanLike(strings.NewReader("x"), "bad", "("), "bad regexp", t)
+	m.likeOutput("bad regexp out", t, `^Invalid regexp \(\(\) in test code: `)
+}
+
+func TestCaptureWriter(t *testing.T) {
+	w := u.NewCaptureWriter()
+	u.Is("", w.WroteString(), "empty", t)
+	fmt.Fprintf(w, "a=%d\n", 1)
+	io.WriteString(w, "b")
+	u.Is("a=1\nb", w.WroteString(), "string", t)
+	got := w.Wrote()
+	got[0] = 'x'
+	u.Is("a=1\nb", w.Wrote(), "bytes copy", t)
+	w.Reset()
+	u.Is("", w.WroteString(), "reset", t)
+}
+
+func TestDistinctByteSlices(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+
+	u.Is(true, s.Is("abc", []byte("abc"), "loose"), "loose", t)
+	s.SetDistinctByteSlices(true)
+	u.Is(true, s.Is([]byte("abc"), []byte("abc"), "bytes"), "bytes", t)
+	m.isOutput("pass out", t)
+	u.Is(false, s.Is("abc", []byte("abc"), "strict"), "strict", t)
+	m.isOutput("strict out", t,
+		`Got "abc" ([]byte) not "abc" (string) for strict.`)
+}
+
+func TestForEach(t *testing.T) {
+	var seen []string
+	u.ForEach(t, map[string]fmt.Stringer{
+		"second": time.Second,
+		"minute": time.Minute,
+	}, func(v u.TUTL, d fmt.Stringer) {
+		seen = append(seen, d.String())
+		v.Is(true, strings.HasSuffix(d.String(), "s"), "ends in s")
+	})
+	u.Is("[1m0s 1s]", seen, "in name order", t)
+}
+
+func TestConcurrent(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+
+	var mu sync.Mutex
+	count := 0
+	u.Is(true, s.Concurrent(8, func() {
+		mu.Lock()
+		defer mu.Unlock()
+		count++
+	}), "no panics", t)
+	u.Is(8, count, "all ran", t)
+	m.isOutput("pass out", t)
+
+	count = 0
+	u.Is(false, s.Concurrent(3, func() {
+		mu.Lock()
+		defer mu.Unlock()
+		if count++; 2 == count {
+			panic("second")
+		}
+	}), "panic", t)
+	m.likeOutput("panic out", t, `^Panic in goroutine [1-3] of 3: second\n$`)
+
+	count = 0
+	u.Is(false, s.Concurrent(0, func() { count++ }), "none", t)
+	u.Is(0, count, "none ran", t)
+	m.isOutput("none out", t,
+		"Concurrent() needs a positive goroutine count in test code, not 0.")
+}
+
+func TestSafeGo(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+
+	ran := false
+	wait := s.SafeGo("ok", func() { ran = true })
+	u.Is(true, wait(), "no panic", t)
+	u.Is(true, ran, "ran", t)
+	m.isOutput("pass out", t)
+
+	wait = s.SafeGo("worker", func() { panic("boom") })
+	u.Is(false, wait(), "panic", t)
+	u.Is(false, wait(), "wait again", t)
+	m.likeOutput("panic out", t,
+		"^Panic in goroutine for worker: boom\n"+
+			`goroutine [^\n]*\n[^\n]*TestSafeGo`,
+		"!*runtime/debug.Stack(", "!*panic(")
+
+	passes := []bool{}
+	s.SetHooks(nil, func(_ string, passed bool) {
+		passes = append(passes, passed)
+	})
+	s.SetDescTransform(strings.ToUpper)
+	wait = s.SafeGo("worker", func() { panic("boom") })
+	u.Is(0, len(passes), "hook waits", t)
+	wait()
+	wait()
+	u.Is("[false]", passes, "hook once", t)
+	m.likeOutput("transform out", t, `^Panic in goroutine for WORKER: boom\n`)
+}
+
+func TestAtEnd(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+
+	done := s.AtEnd()
+	s.Is(1, 1, "one")
+	s.Is(2, 2, "two")
+	done()
+	m.isOutput("all passed", t)
+
+	priors := 0
+	s.SetHooks(nil, func(string, bool) { priors++ })
+	done = s.AtEnd()
+	s.Is(1, 1, "one")
+	s.Is(1, 2, "two")
+	s.Like("abc", "abc", "x", "y")
+	done()
+	m.isOutput("summary", t,
+		"Got 2 not 1 for two.",
+		"Not like /x/...", "and Not like /y/...", "In <abc> for abc.",
+		"FAIL: 2 of 3 checks failed")
+	u.Is(3, priors, "prior hook still called", t)
+	s.Is(1, 1, "after")
+	u.Is(4, priors, "prior hook restored", t)
+
+	s.SetHooks(nil, nil)
+	s.SetMaxFailures(1)
+	s.SetDescTransform(strings.ToUpper)
+	done = s.AtEnd()
+	same := func(a, b int) bool { return a == b }
+	u.IsSliceBy([]int{1}, []int{2}, "slice", s, same)
+	u.ThatT(2, "even", &s, func(v int) bool { return 0 == v%2 })
+	u.SameFunc(s, []int{1, 2}, func(i int) int { return i },
+		func(i int) int { return -i })
+	done()
+	m.isOutput("generics", t,
+		"Got 2 not 1 at index 0 for SLICE.",
+		"Got -1 not 1 for input 1.",
+		"...and 1 more failures",
+		"FAIL: 2 of 3 checks failed")
+
+	w := u.New(t)
+	defer w.AtEnd()()
+	w.Is(1, 1, "summary with name")
+}
+
+func TestM(t *testing.T) {
+	got := u.M("a", 1, "b", u.M("c", "x"))
+	u.Is(1, got["a"], "a", t)
+	u.Is("map[c:x]", got["b"], "nested", t)
+	u.Is(u.Map{}, u.M(), "empty", t)
+	u.Is("tutl.M() needs key/value pairs, got 3 arguments",
+		u.GetPanic(func() { u.M("a", 1, "b") }), "odd", t)
+	u.Is("tutl.M() needs string keys, got int (2) as argument 3",
+		u.GetPanic(func() { u.M("a", 1, 2, 3) }), "non-string", t)
+}
+
+func TestMatches(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+
+	spec := u.Map{
+		"id":    "number",
+		"email": `^[^@]+@[^@]+$`,
+		"note":  "any",
+		"owner": u.Map{"name": "string", "admin": false},
+	}
+	good := `{"id": 1, "email": "a@b", "note": null, "extra": 1,
+		"owner": {"name": "x", "admin": false}}`
+	u.Is(0, s.Matches(spec, good, "good"), "good", t)
+	m.isOutput("good out", t)
+
+	bad := map[string]interface{}{"id": "1", "email": "ab",
+		"owner": map[string]interface{}{"name": 2, "admin": true}}
+	u.Is(5, s.Matches(spec, bad, "user"), "bad", t)
+	m.isOutput("bad out", t,
+		`Got "ab" not like /^[^@]+@[^@]+$/ at email for user.`,
+		"Got string not number at id for user.",
+		"No note found for user.",
+		"Got true not false at owner.admin for user.",
+		"Got number not string at owner.name for user.")
+	u.Is(1, s.Matches(u.Map{"a": u.Map{"b": "any"}}, `{"a": []}`, "arr"),
+		"not object", t)
+	m.isOutput("not object out", t, "Got array not object at a for arr.")
+}
+
+func TestHasMap(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+
+	body := `{"id": 7, "tags": ["a", "b"], "owner": {"name": "x"}}`
+	u.Is(0, s.HasMap("ok", body, u.Map{"id": 7, "tags.1": "b",
+		"owner": u.M("name", "x"), "tags": []string{"a", "b"}}), "ok", t)
+	m.isOutput("ok out", t)
+
+	u.Is(4, s.HasMap("order", body, u.Map{"id": "7", "tags.2": "c",
+		"owner.name": "y", "ch": make(chan int)}), "fails", t)
+	m.isOutput("fails out", t,
+		"Can't convert chan int to JSON in test code at ch for order:"+
+			" json: unsupported type: chan int",
+		`Got 7 not "7" at id for order.`,
+		`Got "x" not "y" at owner.name for order.`,
+		"No tags.2 found for order.")
+	u.Is(2, s.HasMap("nested", body, u.M("owner", u.M("name", "y", "id", 1),
+		"id", 7)), "nested", t)
+	m.isOutput("nested out", t,
+		"No owner.id found for nested.",
+		`Got "x" not "y" at owner.name for nested.`)
+	u.Is(1, u.HasMap(m, "bad", `{`, u.Map{}), "invalid", t)
+
+	u.Is(0, u.CountChecks(nil), "no checks", t)
+	u.Is(3, u.CountChecks(u.M("a", 1, "b", u.M("c", 2, "d", u.Map{}),
+		"e", map[string]interface{}{"f": nil})), "count", t)
+	m.likeOutput("invalid out", t, "^Invalid JSON for bad: ")
+}
+
+func TestContainsSlice(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+
+	got := []int{1, 2, 1, 2, 3}
+	u.Is(true, s.ContainsSlice([]int{2, 3}, got, "end"), "end", t)
+	u.Is(true, s.ContainsSlice([]string{"1", "2", "3"}, got, "strs"), "strs", t)
+	u.Is(true, s.ContainsSlice([]int{}, got, "empty"), "empty", t)
+	m.isOutput("pass out", t)
+	u.Is(false, s.ContainsSlice([]int{1, 3}, got, "gap"), "gap", t)
+	m.isOutput("gap out", t, "Got [1 2 1 2 3] not containing [1 3] for gap.")
+	u.Is(false, s.ContainsSlice(1, got, "int"), "not slice", t)
+	m.isOutput("not slice out", t,
+		"Can't compare elements for int: need a slice or array not int")
+}
+
+func TestTimesInUTC(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+
+	east := time.FixedZone("EST", -5*3600)
+	when := time.Date(2024, 3, 1, 10, 0, 0, 0, east)
+	u.Is("2024-03-01 15:00:00 +0000 UTC", s.S(when), "S", t)
+	u.Is("2024-03-01 10:00:00 -0500 EST", s.V(when), "V unchanged", t)
+	s.Is(when.Add(time.Hour), when, "hour")
+	m.isOutput("UTC out", t, "\nGot 2024-03-01 15:00:00 +0000 UTC\n"+
+		"not 2024-03-01 16:00:00 +0000 UTC\nfor hour.")
+	u.Is(false, s.Is(when.UTC(), when, "zone"), "same instant", t)
+	m.isOutput("same instant out", t,
+		"\nGot 2024-03-01 15:00:00 +0000 UTC (2024-03-01 10:00:00 -0500 EST)"+
+			"\nnot 2024-03-01 15:00:00 +0000 UTC (2024-03-01 15:00:00 +0000 UTC)"+
+			"\nfor zone.")
+
+	s.SetTimesInUTC(false)
+	u.Is("2024-03-01 10:00:00 -0500 EST", s.S(when), "local", t)
+}
+
+type pt struct{ X int }
+
+func (p *pt) String() string   { return fmt.Sprintf("pt(%d)", p.X) }
+func (p *pt) GoString() string { return fmt.Sprintf("&pt{X: %d}", p.X) }
+
+func TestStringerIs(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+
+	u.Is(true, s.StringerIs("1s", time.Second, "second"), "ok", t)
+	m.isOutput("ok out", t)
+	u.Is(false, s.StringerIs("pt(2)", &pt{1}, "pt"), "diff", t)
+	m.isOutput("diff out", t, `Got "pt(1)" not "pt(2)" for pt.`)
+	u.Is(false, s.StringerIs("", nil, "nil"), "nil", t)
+	m.isOutput("nil out", t, "Got nil Stringer for nil.")
+	var np *pt
+	u.Is(false, s.StringerIs("", np, "nil ptr"), "nil ptr", t)
+	m.likeOutput("nil ptr out", t,
+		`^Panic calling \*tutl_test.pt.String\(\) for nil ptr: .*nil pointer`)
+}
+
+func TestGoStringIs(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+
+	u.Is(true, s.GoStringIs("&pt{X: 1}", &pt{1}, "pt"), "ok", t)
+	m.isOutput("ok out", t)
+	u.Is(false, s.GoStringIs("&pt{X: 2}", &pt{1}, "pt"), "diff", t)
+	m.isOutput("diff out", t, `Got "&pt{X: 1}" not "&pt{X: 2}" for pt.`)
+	u.Is(false, s.GoStringIs("", nil, "nil"), "nil", t)
+	m.isOutput("nil out", t, "Got nil GoStringer for nil.")
+	var np *pt
+	u.Is(false, s.GoStringIs("", np, "nil ptr"), "nil ptr", t)
+	m.likeOutput("nil ptr out", t,
+		`^Panic calling \*tutl_test.pt.GoString\(\) for nil ptr: `)
+}
+
+func TestPanicsWithType(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+
+	err := fmt.Errorf("boom")
+	got := s.PanicsWithType("*errors.errorString", "typed", func() {
+		panic(err)
+	})
+	u.Is(err, got, "returned value", t)
+	m.isOutput("typed out", t)
+
+	got = s.PanicsWithType("*errors.errorString", "string", func() {
+		panic("boom")
+	})
+	u.Is("boom", got, "returned string", t)
+	m.isOutput("string out", t,
+		`Got "string" not "*errors.errorString" for string.`)
+	u.Is(nil, s.PanicsWithType("string", "calm", func() {}), "no panic", t)
+	m.isOutput("no panic out", t, "No panic for calm.")
+}
+
+func TestPanicsThat(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+
+	type failure struct{ Code int }
+	isCode := func(code int) func(interface{}) bool {
+		return func(v interface{}) bool {
+			f, ok := v.(failure)
+			return ok && code == f.Code
+		}
+	}
+	boom := func() { panic(failure{7}) }
+	u.Is(true, s.PanicsThat("code", boom, isCode(7)), "match", t)
+	m.isOutput("match out", t)
+	u.Is(false, s.PanicsThat("code", boom, isCode(8)), "no match", t)
+	m.isOutput("no match out", t, "Got panic {7} not matching predicate for code.")
+	u.Is(false, s.PanicsThat("calm", func() {}, isCode(7)), "no panic", t)
+	m.isOutput("no panic out", t, "No panic for calm.")
+	u.Is(false, s.PanicsThat("pred", boom, func(v interface{}) bool {
+		return 0 == v.(int)
+	}), "pred panics", t)
+	m.likeOutput("pred panics out", t, "^Panic in predicate for pred: ")
+}
+
+func TestIsSliceBy(t *testing.T) {
+	m := new(mock)
+
+	type user struct {
+		ID   int
+		Seen int
+	}
+	sameID := func(a, b user) bool { return a.ID == b.ID }
+	want := []user{{1, 0}, {2, 0}}
+	u.Is(true, u.IsSliceBy(want, []user{{1, 5}, {2, 6}}, "ids", m, sameID),
+		"same IDs", t)
+	m.isOutput("same out", t)
+	u.Is(false, u.IsSliceBy(want, []user{{1, 5}, {3, 6}}, "ids", m, sameID),
+		"diff ID", t)
+	m.isOutput("diff out", t, "Got {3 6} not {2 0} at index 1 for ids.")
+	u.Is(false, u.IsSliceBy(want, want[:1], "short", m, sameID), "length", t)
+	m.isOutput("length out", t, "Got length 1 not 2 for short.")
+}
+
+type flaky struct{ n *int }
+
+func (f flaky) MarshalJSON() ([]byte, error) {
+	*f.n++
+	return []byte(fmt.Sprint(*f.n / 3)), nil
+}
+
+func TestJsonDeterministic(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+
+	u.Is(true, s.JsonDeterministic(map[string]int{"b": 1, "a": 2}, "map"),
+		"map", t)
+	m.isOutput("map out", t)
+	n := 0
+	u.Is(false, s.JsonDeterministic(flaky{&n}, "flaky"), "flaky", t)
+	m.isOutput("flaky out", t, "Got 1 from encoding 3 not 0 for flaky.")
+	u.Is(false, s.JsonDeterministic(func() {}, "func"), "func", t)
+	m.likeOutput("func out", t, "^Can't marshal func to JSON: ")
+}
+
+func TestIsAfter(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+
+	lower := func(v interface{}) interface{} {
+		return strings.ToLower(u.V(v))
+	}
+	u.Is(true, s.IsAfter(lower, "Hello", "hELLO", "case"), "case", t)
+	m.isOutput("case out", t)
+	u.Is(false, s.IsAfter(lower, "Hello", "Help", "greet"), "differ", t)
+	m.isOutput("differ out", t, `Got "help" not "hello" for greet.`,
+		`Originals: got "Help" not "Hello".`)
+	u.Is(false, s.IsAfter(lower, "hi", "ho", "same"), "unchanged", t)
+	m.isOutput("unchanged out", t, `Got "ho" not "hi" for same.`)
+	s.SetShowOriginal(false)
+	u.Is(false, s.IsAfter(lower, "Hello", "Help", "greet"), "no orig", t)
+	m.isOutput("no orig out", t, `Got "help" not "hello" for greet.`)
+}
+
+func TestDrainChan(t *testing.T) {
+	m := new(mock)
+
+	ch := make(chan int)
+	go func() {
+		for i := 1; i <= 3; i++ {
+			ch <- i
+		}
+		close(ch)
+	}()
+	u.Is("[1 2 3]", u.DrainChan(ch, time.Minute, m), "closed", t)
+	m.isOutput("closed out", t)
+
+	open := make(chan string, 1)
+	open <- "a"
+	u.Is("[a]", u.DrainChan(open, time.Millisecond, m), "timeout", t)
+	m.isOutput("timeout out", t, "Channel not closed after 1ms (got 1 values).")
+}
+
+func TestRawOutput(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+
+	s.SetRawOutput(true)
+	u.Is("a\tb", s.S("a\tb"), "no quotes or escapes", t)
+	u.Is("x=1 y", s.S("x=", 1, []byte(" y")), "concatenated", t)
+	s.Is("it", "bye\x01", "raw")
+	m.isOutput("raw out", t, "Got bye\x01 not it for raw.")
+}
+
+func TestIsAll(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+
+	isInt := func(got interface{}, desc string, t u.TestingT) bool {
+		return u.HasType("int", got, desc, t)
+	}
+	u.Is(0, s.IsAll(5, "five", u.NotZero, isInt), "all pass", t)
+	m.isOutput("pass out", t)
+	u.Is(2, s.IsAll("", "blank", u.NotZero, isInt, u.IsUTF8), "two fail", t)
+	m.isOutput("fail out", t,
+		"Got zero string for blank.", `Got "string" not "int" for blank.`)
+}
+
+func sliceStream(vals ...int) func() (int, bool) {
+	return func() (int, bool) {
+		if 0 == len(vals) {
+			return 0, false
+		}
+		v := vals[0]
+		vals = vals[1:]
+		return v, true
+	}
+}
+
+func TestIsStreamSorted(t *testing.T) {
+	m := new(mock)
+
+	u.Is(true, u.IsStreamSorted(sliceStream(1, 2, 3), sliceStream(1, 2, 3),
+		"same", m), "same", t)
+	m.isOutput("same out", t)
+	u.IsStreamSorted(sliceStream(1, 2, 3), sliceStream(1, 4), "diff", m)
+	m.isOutput("diff out", t, "Got 4 at position 1 not 2 for diff.")
+	u.IsStreamSorted(sliceStream(1, 2), sliceStream(1), "short", m)
+	m.isOutput("short out", t,
+		"Got end of stream at position 1 not 2 for short.")
+	u.IsStreamSorted(sliceStream(1), sliceStream(1, 2), "long", m)
+	m.isOutput("long out", t,
+		"Got 2 at position 1 not end of stream for long.")
+}
+
+func TestEventuallyNoError(t *testing.T) {
+	m := new(mock)
+	s := u.New(m)
+
+	tries := 0
+	u.Is(true, s.EventuallyNoError("ready", time.Minute, time.Millisecond,
+		func() error {
+			if tries++; tries < 3 {
+				return fmt.Errorf("not yet")
+			}
+			return nil
+		}), "ready", t)
+	u.Is(3, tries, "tries", t)
+	m.isOutput("ready out", t)
+
+	u.Is(false, s.EventuallyNoError("never", 5*time.Millisecond,
+		time.Millisecond, func() error { return fmt.Errorf("down") }),
+		"never", t)
+	m.isOutput("never out", t, "Still got error after 5ms for never: down")
+
+	tries = 0
+	u.Is(false, s.EventuallyNoError("spin", time.Millisecond, 0,
+		func() error { tries++; return fmt.Errorf("down") }), "spin", t)
+	u.Is(0, tries, "spin tries", t)
+	m.isOutput("spin out", t, "EventuallyNoError() needs a positive interval"+
+		" in test code, not 0s, for spin.")
+}
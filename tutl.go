@@ -28,9 +28,15 @@ See also "go doc github.com/TyeMcQueen/go-tutl/hang".
 package tutl
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
 )
 
 // TestingT is an interface covering the methods of '*testing.T' that TUTL
@@ -85,6 +91,72 @@ func (out FakeTester) Failed() bool {
 	return out.HasFailed
 }
 
+// An ErrorCollector is a replacement for a '*testing.T' that collects the
+// reported failures so that they can be returned as an 'error'.  This is
+// useful when using TUTL assertions to implement validation code:
+//
+//      func (c Config) Validate() error {
+//          var ec tutl.ErrorCollector
+//          u := tutl.New(&ec)
+//          u.Like(c.Name, "name", `^\w+$`)
+//          u.NotEmpty(c.Hosts, "hosts")
+//          return ec.Err()
+//      }
+//
+// Messages passed to Log() or Logf() are kept in 'Logs' but are not
+// included in Err().  An ErrorCollector can be used from multiple
+// goroutines.
+//
+type ErrorCollector struct {
+	mu     sync.Mutex
+	Errors []string
+	Logs   []string
+}
+
+func (ec *ErrorCollector) Helper() {}
+
+func (ec *ErrorCollector) Log(args ...interface{}) {
+	ec.add(&ec.Logs, strings.TrimSuffix(fmt.Sprintln(args...), "\n"))
+}
+
+func (ec *ErrorCollector) Logf(format string, args ...interface{}) {
+	ec.add(&ec.Logs, fmt.Sprintf(format, args...))
+}
+
+func (ec *ErrorCollector) Error(args ...interface{}) {
+	ec.add(&ec.Errors, strings.TrimSuffix(fmt.Sprintln(args...), "\n"))
+}
+
+func (ec *ErrorCollector) Errorf(format string, args ...interface{}) {
+	ec.add(&ec.Errors, fmt.Sprintf(format, args...))
+}
+
+func (ec *ErrorCollector) Failed() bool {
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
+	return 0 < len(ec.Errors)
+}
+
+func (ec *ErrorCollector) add(list *[]string, msg string) {
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
+	*list = append(*list, strings.TrimPrefix(msg, "\n"))
+}
+
+// Err() returns 'nil' if no failures have been reported.  Otherwise it
+// returns an error that joins all of the failure messages [via
+// errors.Join()] so that each is on a separate line.
+//
+func (ec *ErrorCollector) Err() error {
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
+	errs := make([]error, len(ec.Errors))
+	for i, msg := range ec.Errors {
+		errs[i] = errors.New(msg)
+	}
+	return errors.Join(errs...)
+}
+
 // TUTL is a type used to allow an alternate calling style, especially for
 // Is() and Like().
 //
@@ -134,6 +206,149 @@ type TUTL struct {
 //
 func New(t TestingT) TUTL { return TUTL{t, Default} }
 
+// failWatcher wraps a TestingT to note whether any failures get reported
+// through it.
+//
+type failWatcher struct {
+	TestingT
+	failed bool
+}
+
+func (w *failWatcher) Error(args ...interface{}) {
+	w.TestingT.Helper()
+	w.failed = true
+	w.TestingT.Error(args...)
+}
+
+func (w *failWatcher) Errorf(format string, args ...interface{}) {
+	w.TestingT.Helper()
+	w.failed = true
+	w.TestingT.Errorf(format, args...)
+}
+
+// Group() runs a group of related assertions and returns whether all of
+// them passed.  The TUTL passed to 'run' reports failures to 't' as usual.
+// This makes it easy to skip tests that depend on a group of preconditions:
+//
+//      if !tutl.Group(t, func(u tutl.TUTL) {
+//          u.Is(nil, err, "open error")
+//          u.Is(3, len(rows), "row count")
+//      }) {
+//          return
+//      }
+//
+func Group(t TestingT, run func(u TUTL)) bool {
+	t.Helper()
+	return New(t).Group(run)
+}
+
+// Same as the non-method tutl.Group() except that the TUTL passed to 'run'
+// has the same option settings as the invoking TUTL object.
+//
+func (u TUTL) Group(run func(u TUTL)) bool {
+	u.Helper()
+	w := &failWatcher{TestingT: u.TestingT}
+	run(TUTL{w, u.o})
+	return !w.failed
+}
+
+// Sub() runs 'run' as a sub-test via 't.Run(name, ...)'.  The TUTL passed
+// to 'run' reports to the sub-test's '*testing.T' and has its own copy of
+// the option settings of the invoking TUTL object.  So option changes
+// made inside of 'run' [such as via EscapeNewline() or SetDigits64()]
+// can't leak into other sub-tests.
+//
+//      u := tutl.New(t)
+//      u.Sub("escaped", t, func(u tutl.TUTL) {
+//          u.EscapeNewline(true)
+//          u.Is("a\nb", Join("a", "b"), "joined")
+//      })
+//
+// Sub() returns what 't.Run()' returns (whether the sub-test passed).
+//
+func (u TUTL) Sub(name string, t *testing.T, run func(u TUTL)) bool {
+	t.Helper()
+	o := u.o
+	return t.Run(name, func(t *testing.T) {
+		run(TUTL{t, o})
+	})
+}
+
+// indenter wraps a TestingT to prepend a prefix to each line of output.
+type indenter struct {
+	TestingT
+	prefix string
+}
+
+// indent() prepends the prefix to each non-empty line of 'msg'.
+func (in indenter) indent(msg string) string {
+	lines := strings.Split(msg, "\n")
+	for i, l := range lines {
+		if "" != l {
+			lines[i] = in.prefix + l
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (in indenter) Error(args ...interface{}) {
+	in.TestingT.Helper()
+	in.TestingT.Error(in.indent(strings.TrimSuffix(fmt.Sprintln(args...), "\n")))
+}
+
+func (in indenter) Errorf(format string, args ...interface{}) {
+	in.TestingT.Helper()
+	in.TestingT.Error(in.indent(fmt.Sprintf(format, args...)))
+}
+
+func (in indenter) Log(args ...interface{}) {
+	in.TestingT.Helper()
+	in.TestingT.Log(in.indent(strings.TrimSuffix(fmt.Sprintln(args...), "\n")))
+}
+
+func (in indenter) Logf(format string, args ...interface{}) {
+	in.TestingT.Helper()
+	in.TestingT.Log(in.indent(fmt.Sprintf(format, args...)))
+}
+
+// WithIndent() returns a copy of the invoking TUTL object that prepends
+// 'prefix' to every line of output that it produces.  This can make it
+// easier to tell which output came from which scenario or sub-test:
+//
+//      for _, c := range cases {
+//          v := u.WithIndent("[" + c.name + "] ")
+//          v.Is(c.want, Run(c.input), "result")
+//      }
+//
+// When a diagnostic is split over several lines, each line (except for
+// the empty first line that starts a multi-line diagnostic) gets the
+// prefix.  Calling WithIndent() on the returned object adds a second
+// prefix after the first.
+//
+func (u TUTL) WithIndent(prefix string) TUTL {
+	return TUTL{indenter{u.TestingT, prefix}, u.o}
+}
+
+// Same as the non-method tutl.Concurrent() except the '*testing.T'
+// argument is held in the TUTL object and so does not need to be passed as
+// an argument.
+//
+func (u TUTL) Concurrent(goroutines int, run func()) bool {
+	u.Helper()
+	return Concurrent(u, goroutines, run)
+}
+
+// Same as the non-method tutl.IsValidEnum() except the '*testing.T'
+// argument is held in the TUTL object and so does not need to be passed as
+// an argument.
+//
+func (u TUTL) IsValidEnum(
+	got interface{}, desc string, valid ...interface{},
+) bool {
+	u.Helper()
+	return u.o.IsValidEnum(got, desc, u, valid...)
+}
+
 // Same as the non-method tutl.Is() except the '*testing.T' argument is held
 // in the TUTL object and so does not need to be passed as an argument.
 //
@@ -142,6 +357,15 @@ func (u TUTL) Is(want, got interface{}, desc string) bool {
 	return u.o.Is(want, got, desc, u)
 }
 
+// Same as the non-method tutl.IsDigits() except the '*testing.T' argument
+// is held in the TUTL object and so does not need to be passed as an
+// argument.
+//
+func (u TUTL) IsDigits(digits int, want, got interface{}, desc string) bool {
+	u.Helper()
+	return u.o.IsDigits(digits, want, got, desc, u)
+}
+
 // Same as the non-method tutl.IsNot() except the '*testing.T' argument is
 // held in the TUTL object and so does not need to be passed as an argument.
 //
@@ -150,6 +374,80 @@ func (u TUTL) IsNot(hate, got interface{}, desc string) bool {
 	return u.o.IsNot(hate, got, desc, u)
 }
 
+// Same as the non-method tutl.IsAfter() except the '*testing.T' argument
+// is held in the TUTL object and so does not need to be passed as an
+// argument.
+//
+func (u TUTL) IsAfter(
+	normalize func(interface{}) interface{},
+	want, got interface{}, desc string,
+) bool {
+	u.Helper()
+	return u.o.IsAfter(normalize, want, got, desc, u)
+}
+
+// Same as the non-method tutl.That() except the '*testing.T' argument is
+// held in the TUTL object and so does not need to be passed as an argument.
+//
+func (u TUTL) That(
+	got interface{}, desc string, pred func(interface{}) bool,
+) bool {
+	u.Helper()
+	return u.o.That(got, desc, u, pred)
+}
+
+// Same as the non-method tutl.PanicsWithType() except the '*testing.T'
+// argument is held in the TUTL object and so does not need to be passed as
+// an argument.
+//
+func (u TUTL) PanicsWithType(
+	wantType string, desc string, run func(),
+) interface{} {
+	u.Helper()
+	return u.o.PanicsWithType(wantType, desc, u, run)
+}
+
+// Same as the non-method tutl.PanicsThat() except the '*testing.T'
+// argument is held in the TUTL object and so does not need to be passed as
+// an argument.
+//
+func (u TUTL) PanicsThat(
+	desc string, run func(), pred func(interface{}) bool,
+) bool {
+	u.Helper()
+	return u.o.PanicsThat(desc, u, run, pred)
+}
+
+// Same as the non-method tutl.StringerIs() except the '*testing.T'
+// argument is held in the TUTL object and so does not need to be passed as
+// an argument.
+//
+func (u TUTL) StringerIs(want string, got fmt.Stringer, desc string) bool {
+	u.Helper()
+	return u.o.StringerIs(want, got, desc, u)
+}
+
+// Same as the non-method tutl.GoStringIs() except the '*testing.T'
+// argument is held in the TUTL object and so does not need to be passed as
+// an argument.
+//
+func (u TUTL) GoStringIs(want string, got fmt.GoStringer, desc string) bool {
+	u.Helper()
+	return u.o.GoStringIs(want, got, desc, u)
+}
+
+// Same as the non-method tutl.IsAll() except the '*testing.T' argument is
+// held in the TUTL object and so does not need to be passed as an argument
+// (the TUTL object is passed to each check as its TestingT).
+//
+func (u TUTL) IsAll(
+	got interface{}, desc string,
+	checks ...func(got interface{}, desc string, t TestingT) bool,
+) int {
+	u.Helper()
+	return u.o.IsAll(got, desc, u, checks...)
+}
+
 // Same as the non-method tutl.HasType() except the '*testing.T' argument is
 // held in the TUTL object and so does not need to be passed as an argument.
 //
@@ -158,6 +456,24 @@ func (u TUTL) HasType(want string, got interface{}, desc string) bool {
 	return u.o.HasType(want, got, desc, u)
 }
 
+// Same as the non-method tutl.IsZero() except the '*testing.T' argument
+// is held in the TUTL object and so does not need to be passed as an
+// argument.
+//
+func (u TUTL) IsZero(got interface{}, desc string) bool {
+	u.Helper()
+	return u.o.IsZero(got, desc, u)
+}
+
+// Same as the non-method tutl.NotZero() except the '*testing.T' argument
+// is held in the TUTL object and so does not need to be passed as an
+// argument.
+//
+func (u TUTL) NotZero(got interface{}, desc string) bool {
+	u.Helper()
+	return u.o.NotZero(got, desc, u)
+}
+
 // Same as the non-method tutl.Circa() except the '*testing.T' argument is
 // held in the TUTL object and so does not need to be passed as an argument.
 //
@@ -166,6 +482,61 @@ func (u TUTL) Circa(digits int, want, got float64, desc string) bool {
 	return u.o.Circa(digits, want, got, desc, u)
 }
 
+// Same as the non-method tutl.NearULP() except the '*testing.T' argument is
+// held in the TUTL object and so does not need to be passed as an argument.
+//
+func (u TUTL) NearULP(want, got float64, maxULPs int, desc string) bool {
+	u.Helper()
+	return u.o.NearULP(want, got, maxULPs, desc, u)
+}
+
+// Same as the non-method tutl.ParsesAs() except the '*testing.T' argument
+// is held in the TUTL object and so does not need to be passed as an
+// argument.
+//
+func (u TUTL) ParsesAs(want interface{}, got string, desc string) bool {
+	u.Helper()
+	return u.o.ParsesAs(want, got, desc, u)
+}
+
+// Same as the non-method tutl.WithinPercent() except the '*testing.T'
+// argument is held in the TUTL object and so does not need to be passed as
+// an argument.
+//
+func (u TUTL) WithinPercent(baseline, got, percent float64, desc string) bool {
+	u.Helper()
+	return u.o.WithinPercent(baseline, got, percent, desc, u)
+}
+
+// Same as the non-method tutl.IsMultipleOf() except the '*testing.T'
+// argument is held in the TUTL object and so does not need to be passed as
+// an argument.
+//
+func (u TUTL) IsMultipleOf(factor, got int64, desc string) bool {
+	u.Helper()
+	return u.o.IsMultipleOf(factor, got, desc, u)
+}
+
+// Same as the non-method tutl.IsPowerOfTwo() except the '*testing.T'
+// argument is held in the TUTL object and so does not need to be passed as
+// an argument.
+//
+func (u TUTL) IsPowerOfTwo(got int64, desc string) bool {
+	u.Helper()
+	return u.o.IsPowerOfTwo(got, desc, u)
+}
+
+// Same as the non-method tutl.TimeFormatIs() except the '*testing.T'
+// argument is held in the TUTL object and so does not need to be passed as
+// an argument.
+//
+func (u TUTL) TimeFormatIs(
+	want string, got time.Time, layout string, desc string,
+) bool {
+	u.Helper()
+	return u.o.TimeFormatIs(want, got, layout, desc, u)
+}
+
 // Same as the non-method tutl.Like() except the '*testing.T' argument is
 // held in the TUTL object and so does not need to be passed as an argument.
 //
@@ -174,6 +545,348 @@ func (u TUTL) Like(got interface{}, desc string, match ...string) int {
 	return u.o.Like(got, desc, u, match...)
 }
 
+// Same as the non-method tutl.LikeLabeled() except the '*testing.T'
+// argument is held in the TUTL object and so does not need to be passed as
+// an argument.
+//
+func (u TUTL) LikeLabeled(
+	got interface{}, desc string, labeled map[string]string,
+) int {
+	u.Helper()
+	return u.o.LikeLabeled(got, desc, u, labeled)
+}
+
+// Same as the non-method tutl.RegexpMatches() except the '*testing.T'
+// argument is held in the TUTL object and so does not need to be passed as
+// an argument.
+//
+func (u TUTL) RegexpMatches(
+	pattern string, desc string, shouldMatch, shouldNotMatch []string,
+) int {
+	u.Helper()
+	return u.o.RegexpMatches(pattern, desc, u, shouldMatch, shouldNotMatch)
+}
+
+// Same as the non-method tutl.ErrorFormat() except the '*testing.T'
+// argument is held in the TUTL object and so does not need to be passed as
+// an argument.
+//
+func (u TUTL) ErrorFormat(got error, desc string) bool {
+	u.Helper()
+	return u.o.ErrorFormat(got, desc, u)
+}
+
+// Same as the non-method tutl.ErrorChain() except the '*testing.T'
+// argument is held in the TUTL object and so does not need to be passed as
+// an argument.
+//
+func (u TUTL) ErrorChain(got error, desc string, wants ...error) int {
+	u.Helper()
+	return u.o.ErrorChain(got, desc, u, wants...)
+}
+
+// SetStrictErrorCase() is the same as setting the global
+// 'tutl.Default.StrictErrorCase' value, except it only changes the setting
+// for the invoking TUTL object.
+//
+func (u *TUTL) SetStrictErrorCase(b bool) {
+	u.o.StrictErrorCase = b
+}
+
+// Same as the non-method tutl.EventuallyNoError() except the '*testing.T'
+// argument is held in the TUTL object and so does not need to be passed as
+// an argument.
+//
+func (u TUTL) EventuallyNoError(
+	desc string, timeout, interval time.Duration, run func() error,
+) bool {
+	u.Helper()
+	return u.o.EventuallyNoError(desc, u, timeout, interval, run)
+}
+
+// Same as the non-method tutl.SafeGo() except the '*testing.T' argument
+// is held in the TUTL object and so does not need to be passed as an
+// argument.
+//
+func (u TUTL) SafeGo(desc string, run func()) (wait func() bool) {
+	u.Helper()
+	return u.o.SafeGo(u, desc, run)
+}
+
+// Same as the non-method tutl.IsCanceled() except the '*testing.T'
+// argument is held in the TUTL object and so does not need to be passed as
+// an argument.
+//
+func (u TUTL) IsCanceled(
+	ctx context.Context, timeout time.Duration, desc string, causes ...error,
+) bool {
+	u.Helper()
+	return u.o.IsCanceled(ctx, timeout, desc, u, causes...)
+}
+
+// Same as the non-method tutl.NotCanceled() except the '*testing.T'
+// argument is held in the TUTL object and so does not need to be passed as
+// an argument.
+//
+func (u TUTL) NotCanceled(
+	ctx context.Context, timeout time.Duration, desc string,
+) bool {
+	u.Helper()
+	return u.o.NotCanceled(ctx, timeout, desc, u)
+}
+
+// Same as the non-method tutl.KeyCount() except the '*testing.T' argument
+// is held in the TUTL object and so does not need to be passed as an
+// argument.
+//
+func (u TUTL) KeyCount(want int, got interface{}, desc string) bool {
+	u.Helper()
+	return u.o.KeyCount(want, got, desc, u)
+}
+
+// Same as the non-method tutl.IsEmpty() except the '*testing.T' argument is
+// held in the TUTL object and so does not need to be passed as an argument.
+//
+func (u TUTL) IsEmpty(got interface{}, desc string) bool {
+	u.Helper()
+	return u.o.IsEmpty(got, desc, u)
+}
+
+// Same as the non-method tutl.NotEmpty() except the '*testing.T' argument
+// is held in the TUTL object and so does not need to be passed as an
+// argument.
+//
+func (u TUTL) NotEmpty(got interface{}, desc string) bool {
+	u.Helper()
+	return u.o.NotEmpty(got, desc, u)
+}
+
+// Same as the non-method tutl.SameSet() except the '*testing.T' argument is
+// held in the TUTL object and so does not need to be passed as an argument.
+//
+func (u TUTL) SameSet(want, got []string, desc string) bool {
+	u.Helper()
+	return u.o.SameSet(want, got, desc, u)
+}
+
+// Same as the non-method tutl.InSet() except the '*testing.T' argument is
+// held in the TUTL object and so does not need to be passed as an argument.
+//
+func (u TUTL) InSet(got interface{}, desc string, allowed ...string) bool {
+	u.Helper()
+	return u.o.InSet(got, desc, u, allowed...)
+}
+
+// Same as the non-method tutl.ElementTypes() except the '*testing.T'
+// argument is held in the TUTL object and so does not need to be passed as
+// an argument.
+//
+func (u TUTL) ElementTypes(
+	got []interface{}, desc string, wantTypes ...string,
+) int {
+	u.Helper()
+	return u.o.ElementTypes(got, desc, u, wantTypes...)
+}
+
+// Same as the non-method tutl.NoDuplicates() except the '*testing.T'
+// argument is held in the TUTL object and so does not need to be passed as
+// an argument.
+//
+func (u TUTL) NoDuplicates(got interface{}, desc string) int {
+	u.Helper()
+	return u.o.NoDuplicates(got, desc, u)
+}
+
+// Same as the non-method tutl.ContainsSlice() except the '*testing.T'
+// argument is held in the TUTL object and so does not need to be passed as
+// an argument.
+//
+func (u TUTL) ContainsSlice(want, got interface{}, desc string) bool {
+	u.Helper()
+	return u.o.ContainsSlice(want, got, desc, u)
+}
+
+// Same as the non-method tutl.IsPermutation() except the '*testing.T'
+// argument is held in the TUTL object and so does not need to be passed as
+// an argument.
+//
+func (u TUTL) IsPermutation(want, got interface{}, desc string) bool {
+	u.Helper()
+	return u.o.IsPermutation(want, got, desc, u)
+}
+
+// Same as the non-method tutl.IsUTF8() except the '*testing.T' argument is
+// held in the TUTL object and so does not need to be passed as an argument.
+//
+func (u TUTL) IsUTF8(got interface{}, desc string) bool {
+	u.Helper()
+	return u.o.IsUTF8(got, desc, u)
+}
+
+// Same as the non-method tutl.IsReaderString() except the '*testing.T'
+// argument is held in the TUTL object and so does not need to be passed as
+// an argument.
+//
+func (u TUTL) IsReaderString(want string, got io.Reader, desc string) bool {
+	u.Helper()
+	return u.o.IsReaderString(want, got, desc, u)
+}
+
+// Same as the non-method tutl.ScanLike() except the '*testing.T' argument
+// is held in the TUTL object and so does not need to be passed as an
+// argument.
+//
+func (u TUTL) ScanLike(r io.Reader, desc string, match ...string) int {
+	u.Helper()
+	return u.o.ScanLike(r, desc, u, match...)
+}
+
+// Same as the non-method tutl.FileIs() except the '*testing.T' argument
+// is held in the TUTL object and so does not need to be passed as an
+// argument.
+//
+func (u TUTL) FileIs(want interface{}, path string) bool {
+	u.Helper()
+	return u.o.FileIs(want, path, u)
+}
+
+// Same as the non-method tutl.DirHas() except the '*testing.T' argument
+// is held in the TUTL object and so does not need to be passed as an
+// argument.
+//
+func (u TUTL) DirHas(dir string, wantFiles ...string) int {
+	u.Helper()
+	return u.o.DirHas(dir, u, wantFiles...)
+}
+
+// Same as the non-method tutl.FieldType() except the '*testing.T' argument
+// is held in the TUTL object and so does not need to be passed as an
+// argument.
+//
+func (u TUTL) FieldType(want string, got interface{}, key, desc string) bool {
+	u.Helper()
+	return u.o.FieldType(want, got, key, desc, u)
+}
+
+// Same as the non-method tutl.Element() except the '*testing.T' argument
+// is held in the TUTL object and so does not need to be passed as an
+// argument.
+//
+func (u TUTL) Element(got interface{}, key string) interface{} {
+	u.Helper()
+	return Element(got, key, u)
+}
+
+// Same as the non-method tutl.JsonFields() except the '*testing.T' argument
+// is held in the TUTL object and so does not need to be passed as an
+// argument.
+//
+func (u TUTL) JsonFields(want []string, got interface{}, desc string) int {
+	u.Helper()
+	return u.o.JsonFields(want, got, desc, u)
+}
+
+// Same as the non-method tutl.MarshalsTo() except the '*testing.T'
+// argument is held in the TUTL object and so does not need to be passed as
+// an argument.
+//
+func (u TUTL) MarshalsTo(want string, got interface{}, desc string) bool {
+	u.Helper()
+	return u.o.MarshalsTo(want, got, desc, u)
+}
+
+// Same as the non-method tutl.StructDiff() except the '*testing.T'
+// argument is held in the TUTL object and so does not need to be passed as
+// an argument.
+//
+func (u TUTL) StructDiff(want, got interface{}, desc string) int {
+	u.Helper()
+	return u.o.StructDiff(want, got, desc, u)
+}
+
+// Same as the non-method tutl.JsonArrayUnordered() except the
+// '*testing.T' argument is held in the TUTL object and so does not need to
+// be passed as an argument.
+//
+func (u TUTL) JsonArrayUnordered(want, got interface{}, desc string) bool {
+	u.Helper()
+	return u.o.JsonArrayUnordered(want, got, desc, u)
+}
+
+// Same as the non-method tutl.IsJsonNear() except the '*testing.T'
+// argument is held in the TUTL object and so does not need to be passed as
+// an argument.
+//
+func (u TUTL) IsJsonNear(
+	want, got interface{}, tolerance float64, desc string,
+) int {
+	u.Helper()
+	return u.o.IsJsonNear(want, got, tolerance, desc, u)
+}
+
+// Same as the non-method tutl.HasInt() except the '*testing.T' argument
+// is held in the TUTL object and so does not need to be passed as an
+// argument.
+//
+func (u TUTL) HasInt(got interface{}, desc string, pairs ...interface{}) int {
+	u.Helper()
+	return u.o.HasInt(got, desc, u, pairs...)
+}
+
+// Same as the non-method tutl.AgreeOn() except the '*testing.T' argument
+// is held in the TUTL object and so does not need to be passed as an
+// argument.
+//
+func (u TUTL) AgreeOn(a, b interface{}, keys []string, desc string) int {
+	u.Helper()
+	return u.o.AgreeOn(a, b, keys, desc, u)
+}
+
+// Same as the non-method tutl.SnapshotValue() except the '*testing.T'
+// argument is held in the TUTL object and so does not need to be passed as
+// an argument.
+//
+func (u TUTL) SnapshotValue(name string, got interface{}) bool {
+	u.Helper()
+	return u.o.SnapshotValue(name, got, u)
+}
+
+// Same as the non-method tutl.Matches() except the '*testing.T' argument
+// is held in the TUTL object and so does not need to be passed as an
+// argument.
+//
+func (u TUTL) Matches(spec Map, got interface{}, desc string) int {
+	u.Helper()
+	return u.o.Matches(spec, got, desc, u)
+}
+
+// Same as the non-method tutl.HasMap() except the '*testing.T' argument
+// is held in the TUTL object and so does not need to be passed as an
+// argument.
+//
+func (u TUTL) HasMap(desc string, got interface{}, want Map) int {
+	u.Helper()
+	return u.o.HasMap(u, desc, got, want)
+}
+
+// Same as the non-method tutl.JsonDeterministic() except the '*testing.T'
+// argument is held in the TUTL object and so does not need to be passed as
+// an argument.
+//
+func (u TUTL) JsonDeterministic(value interface{}, desc string) bool {
+	u.Helper()
+	return u.o.JsonDeterministic(value, desc, u)
+}
+
+// Same as the non-method tutl.Unchanged() except the '*testing.T' argument
+// is held in the TUTL object and so does not need to be passed as an
+// argument.
+//
+func (u TUTL) Unchanged(desc string, value interface{}, run func()) bool {
+	u.Helper()
+	return u.o.Unchanged(desc, u, value, run)
+}
+
 // Same as the non-method tutl.S() except that it honors the option settings
 // of the invoking TUTL object, not of the 'tutl.Default' global.
 //
@@ -205,6 +918,14 @@ func (u *TUTL) SetLineWidth(w int) {
 	u.o.LineWidth = w
 }
 
+// SetNoLeadingNewline() is the same as setting the global
+// 'tutl.Default.NoLeadingNewline' value, except it only changes the setting
+// for the invoking TUTL object.
+//
+func (u *TUTL) SetNoLeadingNewline(b bool) {
+	u.o.NoLeadingNewline = b
+}
+
 // SetPathLength() is the same as setting the global 'tutl.Default.PathLength'
 // except it only changes the setting for the invoking TUTL object.
 //
@@ -212,6 +933,14 @@ func (u *TUTL) SetPathLength(l int) {
 	u.o.PathLength = l
 }
 
+// SetMeasurePath() is the same as setting the global
+// 'tutl.Default.MeasurePath' value, except it only changes the setting for
+// the invoking TUTL object.
+//
+func (u *TUTL) SetMeasurePath(b bool) {
+	u.o.MeasurePath = b
+}
+
 // SetDigits32() is the same as setting the global 'tutl.Default.Digits32'
 // value, except it only changes the setting for the invoking TUTL object.
 //
@@ -226,6 +955,195 @@ func (u TUTL) SetDigits64(d int) {
 	u.o.Digits64 = d
 }
 
+// SetMaxDescLen() is the same as setting the global
+// 'tutl.Default.MaxDescLen' value, except it only changes the setting for
+// the invoking TUTL object.
+//
+func (u *TUTL) SetMaxDescLen(l int) {
+	u.o.MaxDescLen = l
+}
+
+// SetDescTransform() is the same as setting the global
+// 'tutl.Default.DescTransform' value, except it only changes the setting
+// for the invoking TUTL object.
+//
+func (u *TUTL) SetDescTransform(f func(string) string) {
+	u.o.DescTransform = f
+}
+
+// SetHooks() is the same as setting the global 'tutl.Default.BeforeAssert'
+// and 'tutl.Default.AfterAssert' values, except it only changes the
+// settings for the invoking TUTL object.
+//
+func (u *TUTL) SetHooks(
+	before func(desc string), after func(desc string, passed bool),
+) {
+	u.o.BeforeAssert = before
+	u.o.AfterAssert = after
+}
+
+// AtEnd() starts counting the assertions made via the invoking TUTL object
+// and returns a function to be deferred that logs a one-line summary, but
+// only if any of those assertions failed:
+//
+//      u := tutl.New(t)
+//      defer u.AtEnd()()
+//
+// The summary is similar to "FAIL: 3 of 40 checks failed in TestFoo" (the
+// test name is only included if the TestingT has a Name() method, as
+// '*testing.T' does).  Nothing is logged unless the test has Failed().
+//
+// The counting is done via an AfterAssert hook [see SetHooks()] that also
+// calls any AfterAssert hook that was already set.  The returned function
+// restores the prior hook.  Only assertions made via the invoking TUTL
+// object (or copies made of it after AtEnd() was called) are counted.
+// This includes generic functions like IsSliceBy() when the TUTL is passed
+// to them as their TestingT argument, and those made in sub-tests run via
+// Sub().  But checks made in the sub-tests run by ForEach() are not
+// counted, since ForEach() creates new TUTLs from a '*testing.T'.
+//
+func (u *TUTL) AtEnd() func() {
+	prior := u.o.AfterAssert
+	ran, failed := 0, 0
+	u.o.AfterAssert = func(desc string, passed bool) {
+		ran++
+		if !passed {
+			failed++
+		}
+		if nil != prior {
+			prior(desc, passed)
+		}
+	}
+	return func() {
+		u.Helper()
+		u.o.AfterAssert = prior
+		if !u.Failed() {
+			return
+		}
+		in := ""
+		if n, ok := u.TestingT.(interface{ Name() string }); ok {
+			in = " in " + n.Name()
+		}
+		u.Logf("FAIL: %d of %d checks failed%s", failed, ran, in)
+	}
+}
+
+// SetMaxFailures() is the same as setting the global
+// 'tutl.Default.MaxFailures' value, except it only changes the setting for
+// the invoking TUTL object.
+//
+func (u *TUTL) SetMaxFailures(n int) {
+	u.o.MaxFailures = n
+}
+
+// SetTrimLikeInput() is the same as setting the global
+// 'tutl.Default.TrimLikeInput' value, except it only changes the setting
+// for the invoking TUTL object.
+//
+func (u *TUTL) SetTrimLikeInput(b bool) {
+	u.o.TrimLikeInput = b
+}
+
+// SetRecurseDirs() is the same as setting the global
+// 'tutl.Default.RecurseDirs' value, except it only changes the setting for
+// the invoking TUTL object.
+//
+func (u *TUTL) SetRecurseDirs(b bool) {
+	u.o.RecurseDirs = b
+}
+
+// SetMaxDepth() is the same as setting the global 'tutl.Default.MaxDepth'
+// value, except it only changes the setting for the invoking TUTL object.
+//
+func (u *TUTL) SetMaxDepth(d int) {
+	u.o.MaxDepth = d
+}
+
+// SetDistinctByteSlices() is the same as setting the global
+// 'tutl.Default.DistinctByteSlices' value, except it only changes the
+// setting for the invoking TUTL object.
+//
+func (u *TUTL) SetDistinctByteSlices(b bool) {
+	u.o.DistinctByteSlices = b
+}
+
+// SetTimesInUTC() is the same as setting the global
+// 'tutl.Default.TimesInUTC' value, except it only changes the setting for
+// the invoking TUTL object.
+//
+func (u *TUTL) SetTimesInUTC(b bool) {
+	u.o.TimesInUTC = b
+}
+
+// SetRawOutput() is the same as setting the global 'tutl.Default.RawOutput'
+// value, except it only changes the setting for the invoking TUTL object.
+//
+func (u *TUTL) SetRawOutput(b bool) {
+	u.o.RawOutput = b
+}
+
+// SetShowOriginal() is the same as setting the global
+// 'tutl.Default.ShowOriginal' value, except it only changes the setting for
+// the invoking TUTL object.
+//
+func (u *TUTL) SetShowOriginal(b bool) {
+	u.o.ShowOriginal = b
+}
+
+// SetCircaTruncates() is the same as setting the global
+// 'tutl.Default.CircaTruncates' value, except it only changes the setting
+// for the invoking TUTL object.
+//
+func (u *TUTL) SetCircaTruncates(b bool) {
+	u.o.CircaTruncates = b
+}
+
+// SetBoolWords() is the same as setting the global 'tutl.Default.BoolWords'
+// value, except it only changes the setting for the invoking TUTL object.
+//
+func (u *TUTL) SetBoolWords(falseWord, trueWord string) {
+	u.o.BoolWords = [2]string{falseWord, trueWord}
+}
+
+// SetSnapshotDir() is the same as setting the global
+// 'tutl.Default.SnapshotDir' value, except it only changes the setting for
+// the invoking TUTL object.
+//
+func (u *TUTL) SetSnapshotDir(dir string) {
+	u.o.SnapshotDir = dir
+}
+
+// SetVerbose() is the same as setting the global 'tutl.Default.Verbose'
+// value, except it only changes the setting for the invoking TUTL object.
+//
+func (u *TUTL) SetVerbose(b bool) {
+	u.o.Verbose = b
+}
+
+// SetThousandsSep() is the same as setting the global
+// 'tutl.Default.ThousandsSep' value, except it only changes the setting
+// for the invoking TUTL object.
+//
+func (u *TUTL) SetThousandsSep(sep rune) {
+	u.o.ThousandsSep = sep
+}
+
+// SetQuoteLoneString() is the same as setting the global
+// 'tutl.Default.QuoteLoneString' value, except it only changes the setting
+// for the invoking TUTL object.
+//
+func (u *TUTL) SetQuoteLoneString(b bool) {
+	u.o.QuoteLoneString = b
+}
+
+// SetHumanizeBytes() is the same as setting the global
+// 'tutl.Default.HumanizeBytes' value, except it only changes the setting
+// for the invoking TUTL object.
+//
+func (u *TUTL) SetHumanizeBytes(b bool) {
+	u.o.HumanizeBytes = b
+}
+
 // Identical to the non-method tutl.DoubleQuote().
 func (u TUTL) DoubleQuote(s string) string {
 	return DoubleQuote(s)